@@ -0,0 +1,101 @@
+package yagcl_json
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsupportedCompression is returned if the data is compressed using
+// zstd, but no decompressor has been defined via
+// JSONSourceOptionalSetup.Zstd.
+var ErrUnsupportedCompression = errors.New("compression format not supported")
+
+// DecompressFunc wraps a reader of compressed data with a reader returning
+// the decompressed data. If the returned reader implements io.Closer or has
+// a Close method without results, such as *zstd.Decoder, it is closed once
+// the data has been read.
+type DecompressFunc func(io.Reader) (io.Reader, error)
+
+type compressionFormat int
+
+const (
+	uncompressed compressionFormat = iota
+	gzipCompressed
+	bzip2Compressed
+	zstdCompressed
+)
+
+var compressionFormats = []struct {
+	format    compressionFormat
+	magic     []byte
+	extension string
+}{
+	{gzipCompressed, []byte{0x1f, 0x8b}, ".gz"},
+	{bzip2Compressed, []byte("BZh"), ".bz2"},
+	{zstdCompressed, []byte{0x28, 0xb5, 0x2f, 0xfd}, ".zst"},
+}
+
+// decompress detects compressed data by its magic bytes, or for Path sources
+// by the file extension, and returns a reader for the decompressed data.
+// Uncompressed data is returned as is.
+func (s *jsonSourceImpl) decompress(reader io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(reader)
+	// Errors, such as EOF for short documents, are reported when reading.
+	header, _ := buffered.Peek(4)
+
+	format := uncompressed
+	for _, candidate := range compressionFormats {
+		if bytes.HasPrefix(header, candidate.magic) {
+			format = candidate.format
+			break
+		}
+	}
+	if format == uncompressed && s.path != "" {
+		extension := strings.ToLower(filepath.Ext(s.path))
+		for _, candidate := range compressionFormats {
+			if extension == candidate.extension {
+				format = candidate.format
+				break
+			}
+		}
+	}
+
+	switch format {
+	case gzipCompressed:
+		decompressed, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing source '%s': %w", s.sourceName(), err)
+		}
+		return decompressed, nil
+	case bzip2Compressed:
+		return bzip2.NewReader(buffered), nil
+	case zstdCompressed:
+		if s.zstd == nil {
+			return nil, fmt.Errorf("source '%s' is compressed using zstd: %w", s.sourceName(), ErrUnsupportedCompression)
+		}
+		decompressed, err := s.zstd(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing source '%s': %w", s.sourceName(), err)
+		}
+		return decompressed, nil
+	}
+	return buffered, nil
+}
+
+// closeDecompressor releases the resources held by the reader returned by
+// decompress, for example the goroutines of a zstd decoder.
+func closeDecompressor(decompressed io.Reader) {
+	switch closer := decompressed.(type) {
+	case io.Closer:
+		closer.Close()
+	case interface{ Close() }:
+		closer.Close()
+	}
+}
@@ -0,0 +1,159 @@
+package yagcl_json
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipCompress(t *testing.T, data string) []byte {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	_, err := writer.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+// zstdMagic is prepended to fake zstd data, which is decompressed by
+// skipping the magic bytes.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+func fakeZstd(reader io.Reader) (io.Reader, error) {
+	magic := make([]byte, len(zstdMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(magic, zstdMagic) {
+		return nil, errors.New("invalid magic")
+	}
+	return reader, nil
+}
+
+func Test_Parse_Compressed_Reader(t *testing.T) {
+	bzip2Compressed, err := base64.StdEncoding.DecodeString("QlpoOTFBWSZTWZ+qvGgAAAeZgFAAEBAyI0AaIAAimnoI2SEAAATxQZLFWEsHxdyRThQkJ+qvGgA=")
+	require.NoError(t, err)
+
+	for _, value := range []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"gzip", gzipCompress(t, `{"name": "gzip"}`), "gzip"},
+		{"bzip2", bzip2Compressed, "bzip2"},
+		{"zstd", append(append([]byte(nil), zstdMagic...), `{"name": "zstd"}`...), "zstd"},
+		{"uncompressed", []byte(`{"name": "plain"}`), "plain"},
+		{"short", []byte(`{}`), ""},
+	} {
+		t.Run(value.name, func(t *testing.T) {
+			var c CommonConfig
+			err := yagcl.New[CommonConfig]().
+				Add(Source().Reader(bytes.NewReader(value.data)).Zstd(fakeZstd)).
+				Parse(&c)
+			if assert.NoError(t, err) {
+				assert.Equal(t, value.expected, c.Name)
+			}
+		})
+	}
+}
+
+func Test_Parse_Compressed_Path(t *testing.T) {
+	directory := t.TempDir()
+	gzipPath := filepath.Join(directory, "config.json.gz")
+	require.NoError(t, os.WriteFile(gzipPath, gzipCompress(t, `{"name": "gzip"}`), 0o600))
+	// Detected by its extension only.
+	zstdPath := filepath.Join(directory, "config.json.ZST")
+	require.NoError(t, os.WriteFile(zstdPath, []byte(`{"name": "zstd"}`), 0o600))
+
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Path(gzipPath)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "gzip", c.Name)
+	}
+
+	var called bool
+	err = yagcl.New[CommonConfig]().
+		Add(Source().Path(zstdPath).Zstd(func(reader io.Reader) (io.Reader, error) {
+			called = true
+			return reader, nil
+		})).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.True(t, called)
+		assert.Equal(t, "zstd", c.Name)
+	}
+}
+
+// closableDecoder mimics *zstd.Decoder, whose Close method has no results.
+type closableDecoder struct {
+	io.Reader
+	closed bool
+}
+
+func (d *closableDecoder) Close() {
+	d.closed = true
+}
+
+func Test_Parse_Compressed_Close(t *testing.T) {
+	decoder := &closableDecoder{}
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().
+			Reader(bytes.NewReader(append(append([]byte(nil), zstdMagic...), `{"name": "zstd"}`...))).
+			Zstd(func(reader io.Reader) (io.Reader, error) {
+				decompressed, err := fakeZstd(reader)
+				decoder.Reader = decompressed
+				return decoder, err
+			})).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "zstd", c.Name)
+		assert.True(t, decoder.closed)
+	}
+}
+
+func Test_Parse_Compressed_Errors(t *testing.T) {
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Reader(bytes.NewReader(append(append([]byte(nil), zstdMagic...), "{}"...))).Name("zstd")).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrUnsupportedCompression)
+	assert.ErrorContains(t, err, "'zstd'")
+
+	err = yagcl.New[CommonConfig]().
+		Add(Source().Reader(bytes.NewReader([]byte{0x1f, 0x8b, 0x00}))).
+		Parse(&c)
+	assert.Error(t, err)
+
+	err = yagcl.New[CommonConfig]().
+		Add(Source().Reader(bytes.NewReader(zstdMagic)).Zstd(func(io.Reader) (io.Reader, error) {
+			return nil, errors.New("broken")
+		})).
+		Parse(&c)
+	assert.ErrorContains(t, err, "broken")
+}
+
+func Test_Parse_Compressed_MaxBytes(t *testing.T) {
+	// Compresses to far less than the limit.
+	document := `{"name": "a"` + strings.Repeat(" ", 100000) + "}"
+	compressed := gzipCompress(t, document)
+	require.Less(t, len(compressed), 1024)
+
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Reader(bytes.NewReader(compressed)).MaxBytes(1024)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+}
@@ -0,0 +1,101 @@
+package yagcl_json
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_Context_BlockingReader(t *testing.T) {
+	reader, writer := io.Pipe()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Reader(reader).Name("pipe").Context(ctx)).
+		Parse(&c)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "'pipe'")
+
+	// The reader has been closed, unblocking the writer.
+	_, err = writer.Write([]byte("{}"))
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+}
+
+func Test_Parse_Context_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().String(`{"name": "a"}`).Context(ctx)).
+		Parse(&c)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "'<bytes>'")
+	assert.Empty(t, c.Name)
+}
+
+func Test_ParseContext(t *testing.T) {
+	var c CommonConfig
+	loaded, err := Source().Path("./test.json").Must().
+		ParseContext(context.Background(), standardCompanion(), &c)
+	assert.NoError(t, err)
+	assert.True(t, loaded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	loaded, err = Source().Path("./test.json").Must().
+		ParseContext(ctx, standardCompanion(), &c)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "'./test.json'")
+	assert.False(t, loaded)
+}
+
+func Test_Parse_Context_Reader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Reader(io.NopCloser(strings.NewReader(`{"name": "a"}`))).Context(ctx)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a", c.Name)
+	}
+}
+
+// cancellingReader cancels the context after the first read and counts how
+// often it has been read from. It doesn't implement io.Closer.
+type cancellingReader struct {
+	reader io.Reader
+	cancel context.CancelFunc
+	reads  int
+}
+
+func (r *cancellingReader) Read(buffer []byte) (int, error) {
+	r.reads++
+	r.cancel()
+	return r.reader.Read(buffer[:1])
+}
+
+func Test_Parse_Context_NonClosableReader(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader := &cancellingReader{reader: strings.NewReader(`{"name": "a"}`), cancel: cancel}
+
+	// Readers that can't be closed are read on the calling goroutine, so
+	// no read happens after Parse has returned.
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Reader(reader).Context(ctx)).
+		Parse(&c)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, reader.reads)
+	assert.Empty(t, c.Name)
+}
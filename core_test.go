@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/Bios-Marcel/yagcl"
 	"github.com/stretchr/testify/assert"
@@ -188,6 +189,128 @@ func Test_Parse_KeyTags_ExtraValues(t *testing.T) {
 	}
 }
 
+func Test_Parse_KeyTags_OptionsOnly(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a" json:",omitempty"`
+	}
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"field_a": "content a"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "content a", c.FieldA)
+	}
+}
+
+func Test_Parse_KeyTags_Skip(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a" json:"-"`
+		FieldB string `json:"-,"`
+	}
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"field_a": "content a", "-": "content b"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Empty(t, c.FieldA)
+		assert.Equal(t, "content b", c.FieldB)
+	}
+}
+
+func Test_Parse_KeyTags_String(t *testing.T) {
+	type configuration struct {
+		Int     int           `json:"int,string"`
+		Uint    *uint         `json:"uint,string"`
+		Float   float64       `json:"float,string"`
+		Bool    bool          `json:"bool,string"`
+		String  string        `json:"string,string"`
+		Timeout time.Duration `json:"timeout,string"`
+	}
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{
+			"int": "-1",
+			"uint": "2",
+			"float": "1.5",
+			"bool": "true",
+			"string": "\"quoted\"",
+			"timeout": "10s"
+		}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, -1, c.Int)
+		assert.Equal(t, uint(2), *c.Uint)
+		assert.Equal(t, 1.5, c.Float)
+		assert.Equal(t, true, c.Bool)
+		assert.Equal(t, "quoted", c.String)
+		assert.Equal(t, 10*time.Second, c.Timeout)
+	}
+}
+
+func Test_Parse_KeyTags_String_Invalid(t *testing.T) {
+	type configuration struct {
+		Int int `json:"int,string"`
+	}
+
+	for _, value := range []string{
+		`{"int": 1}`,
+		`{"int": "one"}`,
+		`{"int": "\"1\""}`,
+		`{"int": "1 2"}`,
+		`{"int": "1}"}`,
+	} {
+		t.Run(value, func(t *testing.T) {
+			var c configuration
+			err := yagcl.New[configuration]().
+				Add(Source().String(value)).
+				Parse(&c)
+			assert.ErrorIs(t, err, yagcl.ErrParseValue)
+		})
+	}
+}
+
+func Test_Parse_KeyTags_Inline(t *testing.T) {
+	type common struct {
+		Name string `json:"name"`
+	}
+	type server struct {
+		Host string `json:"host"`
+	}
+	type configuration struct {
+		Common common  `json:",inline"`
+		Server *server `json:",inline"`
+		Port   int     `json:"port"`
+	}
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service", "host": "localhost", "port": 80}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.Common.Name)
+		assert.Equal(t, "localhost", c.Server.Host)
+		assert.Equal(t, 80, c.Port)
+	}
+
+	c = configuration{}
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"port": 80}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Nil(t, c.Server)
+	}
+}
+
+func Test_Parse_KeyTags_Inline_NoStruct(t *testing.T) {
+	type configuration struct {
+		Name string `json:",inline"`
+	}
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service"}`)).
+		Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrUnsupportedFieldType)
+}
+
 func Test_Parse_KeyTag_NonEmpty(t *testing.T) {
 	assert.NotEmpty(t, Source().String(`{}`).KeyTag())
 }
@@ -0,0 +1,212 @@
+package yagcl_json
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse_Defaults(t *testing.T) {
+	type nested struct {
+		Port int `key:"port" default:"5432"`
+	}
+	type configuration struct {
+		String   string                  `key:"string" default:"text"`
+		Quoted   string                  `key:"quoted" default:"\"quoted\""`
+		Int      int                     `key:"int" default:"-1"`
+		Float    float64                 `key:"float" default:"1.5"`
+		Bool     bool                    `key:"bool" default:"true"`
+		Timeout  time.Duration           `key:"timeout" default:"30s"`
+		Nanos    time.Duration           `key:"nanos" default:"1000"`
+		Pointer  *int                    `key:"pointer" default:"1"`
+		Slice    []int                   `key:"slice" default:"[1,2,3]"`
+		Map      map[string]int          `key:"map" default:"{\"a\": 1}"`
+		Text     customTextUnmarshalable `key:"text" default:"lower"`
+		JSON     customJSONUnmarshalable `key:"json" default:"lower"`
+		Nested   nested                  `key:"nested"`
+		Optional *nested                 `key:"optional"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "text", c.String)
+		assert.Equal(t, `"quoted"`, c.Quoted)
+		assert.Equal(t, -1, c.Int)
+		assert.Equal(t, 1.5, c.Float)
+		assert.True(t, c.Bool)
+		assert.Equal(t, 30*time.Second, c.Timeout)
+		assert.Equal(t, time.Microsecond, c.Nanos)
+		assert.Equal(t, 1, *c.Pointer)
+		assert.Equal(t, []int{1, 2, 3}, c.Slice)
+		assert.Equal(t, map[string]int{"a": 1}, c.Map)
+		assert.Equal(t, customTextUnmarshalable("LOWER"), c.Text)
+		assert.Equal(t, customJSONUnmarshalable("LOWER"), c.JSON)
+		assert.Equal(t, 5432, c.Nested.Port)
+		assert.Nil(t, c.Optional)
+	}
+}
+
+func Test_Parse_Defaults_KeyPresent(t *testing.T) {
+	type nested struct {
+		Port int    `key:"port" default:"5432"`
+		Host string `key:"host" default:"localhost"`
+	}
+	type configuration struct {
+		Int      int     `key:"int" default:"1"`
+		Null     int     `key:"null" default:"1"`
+		Optional *nested `key:"optional"`
+	}
+
+	var presence Presence
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{"int": 0, "null": null, "optional": {"port": 1}}`).
+			TrackPresence(&presence)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, c.Int)
+		assert.Equal(t, 0, c.Null)
+		assert.Equal(t, 1, c.Optional.Port)
+		assert.Equal(t, "localhost", c.Optional.Host)
+		// Defaults don't count as being set by the document.
+		assert.Equal(t, []string{"int", "optional", "optional.port"}, presence.SetPaths())
+	}
+}
+
+func Test_Parse_Defaults_KeepExistingValues(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a" default:"default a"`
+		FieldB string `key:"field_b" default:"default b"`
+	}
+
+	c := configuration{FieldA: "preset"}
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "preset", c.FieldA)
+		assert.Equal(t, "default b", c.FieldB)
+	}
+
+	// Values of sources with a lower priority aren't overwritten.
+	c = configuration{}
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"field_a": "a"}`)).
+		Add(Source().String(`{"field_b": "b"}`)).
+		AllowOverride().
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a", c.FieldA)
+		assert.Equal(t, "b", c.FieldB)
+	}
+}
+
+func Test_Parse_Defaults_KeyPresentInPreviousSource(t *testing.T) {
+	type configuration struct {
+		Port    int    `key:"port" default:"3"`
+		Verbose bool   `key:"verbose" default:"true"`
+		Name    string `key:"name" default:"name"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"port": 0, "verbose": false}`)).
+		Add(Source().String(`{}`)).
+		AllowOverride().
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 0, c.Port)
+		assert.False(t, c.Verbose)
+		assert.Equal(t, "name", c.Name)
+	}
+}
+
+func Test_Parse_Defaults_Reload(t *testing.T) {
+	type configuration struct {
+		Port int `key:"port" default:"8080"`
+	}
+
+	var provenance Provenance
+	load := func(document string) configuration {
+		var c configuration
+		err := yagcl.New[configuration]().
+			Add(Source().String(document).Name("config.json").TrackProvenance(&provenance)).
+			Parse(&c)
+		assert.NoError(t, err)
+		return c
+	}
+
+	assert.Equal(t, 1, load(`{"port": 1}`).Port)
+	assert.Equal(t, "port: config.json:1:10\n", provenance.Explain())
+
+	// Neither the previous load, nor the Provenance shared with it, prevent
+	// the default from being applied.
+	assert.Equal(t, 8080, load(`{}`).Port)
+	assert.Empty(t, provenance.Explain())
+}
+
+func Test_Parse_Defaults_ReusedLoader(t *testing.T) {
+	type configuration struct {
+		Port int `key:"port" default:"8080"`
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"port": 0}`), 0o600))
+	loader := yagcl.New[configuration]().
+		Add(Source().Path(path)).
+		Add(Source().String(`{}`)).
+		AllowOverride()
+
+	var c configuration
+	if assert.NoError(t, loader.Parse(&c)) {
+		assert.Equal(t, 0, c.Port)
+	}
+
+	// The first source doesn't set the port anymore, so the value it has set
+	// during the previous load mustn't be taken into account.
+	require.NoError(t, os.WriteFile(path, []byte(`{}`), 0o600))
+	c = configuration{}
+	if assert.NoError(t, loader.Parse(&c)) {
+		assert.Equal(t, 8080, c.Port)
+	}
+}
+
+func Test_Parse_Defaults_Invalid(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		var c struct {
+			Int int `key:"int" default:"one"`
+		}
+		assert.ErrorIs(t, parseEmptyDocument(&c), yagcl.ErrParseValue)
+	})
+	t.Run("duration", func(t *testing.T) {
+		var c struct {
+			Timeout time.Duration `key:"timeout" default:"30 seconds"`
+		}
+		assert.ErrorIs(t, parseEmptyDocument(&c), yagcl.ErrParseValue)
+	})
+	t.Run("slice", func(t *testing.T) {
+		var c struct {
+			Slice []int `key:"slice" default:"[\"a\"]"`
+		}
+		assert.ErrorIs(t, parseEmptyDocument(&c), yagcl.ErrParseValue)
+	})
+	t.Run("text unmarshaler", func(t *testing.T) {
+		var c struct {
+			Text intCustomTextUnmarshalable `key:"text" default:"one"`
+		}
+		assert.ErrorIs(t, parseEmptyDocument(&c), yagcl.ErrParseValue)
+	})
+}
+
+func parseEmptyDocument[T any](configuration *T) error {
+	return yagcl.New[T]().Add(Source().String(`{}`)).Parse(configuration)
+}
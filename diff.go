@@ -0,0 +1,178 @@
+package yagcl_json
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ChangeKind defines how a field differs between two documents.
+type ChangeKind int
+
+const (
+	// ChangeAdded means that the field is only set by the new document.
+	ChangeAdded ChangeKind = iota
+	// ChangeRemoved means that the field is only set by the old document.
+	ChangeRemoved
+	// ChangeModified means that both documents set the field to different
+	// values.
+	ChangeModified
+)
+
+// String returns "added", "removed" or "modified".
+func (kind ChangeKind) String() string {
+	switch kind {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(kind))
+}
+
+// Change is a single difference between two documents.
+type Change struct {
+	Kind ChangeKind
+	// Path is the JSON path of the field, such as "database.port".
+	Path string
+	// Old is the parsed value of the old document. It is nil for
+	// ChangeAdded.
+	Old any
+	// New is the parsed value of the new document. It is nil for
+	// ChangeRemoved.
+	New any
+}
+
+// String returns the change in the format "+ path: new", "- path: old" or
+// "~ path: old -> new".
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s: %v", c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s: %v", c.Path, c.Old)
+	}
+	return fmt.Sprintf("~ %s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+// Diff parses both documents into a fresh T and reports all fields whose
+// values differ. Since the parsed values are compared, formatting, comments
+// and the order of keys are irrelevant. Fields are only considered if the
+// document sets them, so defaults don't cause changes. The values of fields
+// tagged with `secret:"true"` are replaced with Redacted. Changes are
+// ordered by the declaration of their fields. The options should match the
+// ones used for loading the documents, see MappingOption.
+func Diff[T any](oldDocument, newDocument []byte, options ...MappingOption) ([]Change, error) {
+	differ := &differ{mapping: newMapping(options)}
+	var oldConfiguration, newConfiguration T
+	if err := differ.parse(oldDocument, "old", &differ.oldPresence, &oldConfiguration); err != nil {
+		return nil, fmt.Errorf("error parsing old document: %w", err)
+	}
+	if err := differ.parse(newDocument, "new", &differ.newPresence, &newConfiguration); err != nil {
+		return nil, fmt.Errorf("error parsing new document: %w", err)
+	}
+
+	err := differ.diffStruct(
+		reflect.ValueOf(&oldConfiguration).Elem(),
+		reflect.ValueOf(&newConfiguration).Elem(),
+		nil)
+	return differ.changes, err
+}
+
+type differ struct {
+	*mapping
+	oldPresence Presence
+	newPresence Presence
+
+	changes []Change
+}
+
+// parse parses the document using the options of the mapping.
+func (d *differ) parse(document []byte, name string, presence *Presence, configuration any) error {
+	source := Source().Bytes(document).Name(name).TrackPresence(presence).
+		KeyMatching(d.source.keyMatching).
+		KeyNaming(d.source.keyNaming).
+		Null(d.source.nullPolicy)
+	_, err := source.Parse(d.companion, configuration)
+	return err
+}
+
+func (d *differ) diffStruct(oldStruct, newStruct reflect.Value, parentJsonPath []string) error {
+	fields, err := d.source.typeFields(d.companion, oldStruct.Type())
+	if err != nil {
+		return err
+	}
+
+	for _, field := range fields {
+		jsonPath := append(parentJsonPath[:len(parentJsonPath):len(parentJsonPath)], field.key)
+		oldValue := dereference(fieldValueOrZero(oldStruct, field))
+		newValue := dereference(fieldValueOrZero(newStruct, field))
+
+		fieldType := extractNonPointerFieldType(field.structField.Type)
+		if fieldType.Kind() == reflect.Struct && !hasCustomUnmarshaler(fieldType) && !isSecret(field.structField) {
+			if !oldValue.IsValid() {
+				oldValue = reflect.Zero(fieldType)
+			}
+			if !newValue.IsValid() {
+				newValue = reflect.Zero(fieldType)
+			}
+			if err := d.diffStruct(oldValue, newValue, jsonPath); err != nil {
+				return err
+			}
+			continue
+		}
+
+		path := formatPath(jsonPath)
+		oldSet, newSet := d.oldPresence.IsSet(path), d.newPresence.IsSet(path)
+		change := Change{Path: path}
+		switch {
+		case oldSet && !newSet:
+			change.Kind = ChangeRemoved
+			change.Old = diffValue(field, oldValue)
+		case !oldSet && newSet:
+			change.Kind = ChangeAdded
+			change.New = diffValue(field, newValue)
+		case oldSet && newSet && !reflect.DeepEqual(interfaceOrNil(oldValue), interfaceOrNil(newValue)):
+			change.Kind = ChangeModified
+			change.Old = diffValue(field, oldValue)
+			change.New = diffValue(field, newValue)
+		default:
+			continue
+		}
+		d.changes = append(d.changes, change)
+	}
+	return nil
+}
+
+func fieldValueOrZero(structValue reflect.Value, field boundField) reflect.Value {
+	if value, reachable := fieldByIndex(structValue, field.index); reachable {
+		return value
+	}
+	return reflect.Zero(field.structField.Type)
+}
+
+// dereference follows all pointers, returning an invalid value for nil.
+func dereference(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func interfaceOrNil(value reflect.Value) any {
+	if !value.IsValid() {
+		return nil
+	}
+	return value.Interface()
+}
+
+func diffValue(field boundField, value reflect.Value) any {
+	if isSecret(field.structField) && value.IsValid() {
+		return Redacted
+	}
+	return interfaceOrNil(value)
+}
@@ -0,0 +1,88 @@
+package yagcl_json
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diff(t *testing.T) {
+	type configuration struct {
+		CommonConfig
+		Timeout  time.Duration `key:"timeout"`
+		Hosts    []string      `key:"hosts"`
+		Password string        `key:"password" secret:"true"`
+		Level    string        `key:"level" default:"info"`
+		Database *struct {
+			Host string `key:"host"`
+			Port int    `key:"port"`
+		} `key:"database"`
+	}
+
+	changes, err := Diff[configuration]([]byte(`{
+		// Old
+		"name": "service",
+		"timeout": "30s",
+		"hosts": ["a", "b"],
+		"password": "old",
+		"database": {"host": "localhost", "port": 5432}
+	}`), []byte(`{
+		"database": {"port": 5433, "host": "localhost"},
+		"password": "new",
+		"hosts": ["a", "b"],
+		"timeout": "30000ms",
+		"verbose": false,
+	}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Change{
+			{Kind: ChangeRemoved, Path: "name", Old: "service"},
+			{Kind: ChangeAdded, Path: "verbose", New: false},
+			{Kind: ChangeModified, Path: "password", Old: Redacted, New: Redacted},
+			{Kind: ChangeModified, Path: "database.port", Old: 5432, New: 5433},
+		}, changes)
+		assert.Equal(t, "- name: service", changes[0].String())
+		assert.Equal(t, "+ verbose: false", changes[1].String())
+		assert.Equal(t, "~ database.port: 5432 -> 5433", changes[3].String())
+	}
+}
+
+func Test_Diff_Equal(t *testing.T) {
+	changes, err := Diff[CommonConfig]([]byte(`{"name": "a", "verbose": true}`), []byte(`{"verbose": true, "name": "a"}`))
+	if assert.NoError(t, err) {
+		assert.Empty(t, changes)
+	}
+}
+
+func Test_Diff_Invalid(t *testing.T) {
+	_, err := Diff[CommonConfig]([]byte(`{"name": 1}`), []byte(`{}`))
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+	assert.ErrorContains(t, err, "old document")
+
+	_, err = Diff[CommonConfig]([]byte(`{}`), []byte(`{"verbose": "yes"}`))
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+	assert.ErrorContains(t, err, "new document")
+}
+
+func Test_Diff_Options(t *testing.T) {
+	type configuration struct {
+		ServerPort int
+		LogLevel   string `cfg:"level"`
+	}
+
+	_, err := Diff[configuration]([]byte(`{}`), []byte(`{}`))
+	assert.ErrorIs(t, err, yagcl.ErrExportedFieldMissingKey)
+
+	changes, err := Diff[configuration](
+		[]byte(`{"server_port": 80, "LEVEL": "info"}`),
+		[]byte(`{"SERVER_PORT": 8080, "level": "debug"}`),
+		WithSource(Source().String(`{}`).KeyNaming(SnakeCase).KeyMatching(KeyMatchCaseInsensitive)),
+		WithLoader(yagcl.New[configuration]().AdditionalKeyTags("cfg")))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []Change{
+			{Kind: ChangeModified, Path: "server_port", Old: 80, New: 8080},
+			{Kind: ChangeModified, Path: "level", Old: "info", New: "debug"},
+		}, changes)
+	}
+}
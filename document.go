@@ -0,0 +1,391 @@
+package yagcl_json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/buger/jsonparser"
+)
+
+// ErrPathConflict is returned by Document.Set if a value on the path to the
+// value being set isn't an object.
+var ErrPathConflict = errors.New("path conflicts with the document")
+
+// ErrUnknownField is returned if a field path doesn't resolve to a field
+// bound to a JSON key.
+var ErrUnknownField = errors.New("no field bound to a key found")
+
+// Document is a JSON document, which may contain comments, that can be
+// edited without touching anything except the values being set. Comments,
+// whitespace and the order of keys are preserved.
+type Document struct {
+	data    []byte
+	mapping *mapping
+}
+
+// ParseDocument parses a JSON document, which has to contain an object. The
+// options should match the ones used for loading the document, so that
+// existing keys are found by the same rules, see MappingOption.
+func ParseDocument(data []byte, options ...MappingOption) (*Document, error) {
+	root, err := decodeTree(blankComments(data))
+	if err != nil {
+		return nil, newJsonparserError(nil, err)
+	}
+	if _, ok := root.(map[string]any); !ok {
+		return nil, fmt.Errorf("document has to contain an object, but contained %s: %w", typeName(root), yagcl.ErrParseValue)
+	}
+
+	return &Document{data: append([]byte(nil), data...), mapping: newMapping(options)}, nil
+}
+
+// Bytes returns the serialized document.
+func (d *Document) Bytes() []byte {
+	return append([]byte(nil), d.data...)
+}
+
+// Set sets the value at the given path of keys, encoding it the same way
+// Marshal does. Existing values are replaced, while missing keys are
+// appended to their parent object. Keys are matched using the
+// KeyMatchingPolicy of the document's options, so that no ambiguous keys are
+// added. The indentation of newly added keys follows the surrounding
+// document.
+func (d *Document) Set(path []string, value any) error {
+	if len(path) == 0 {
+		return fmt.Errorf("path is empty: %w", ErrPathConflict)
+	}
+
+	blanked := blankComments(d.data)
+	rootValue, _, rootEnd, err := jsonparser.Get(blanked)
+	if err != nil {
+		return newJsonparserError(nil, err)
+	}
+	objectStart, objectEnd := rootEnd-len(rootValue), rootEnd
+
+	for index, key := range path {
+		member, err := d.mapping.source.lookupKey(blanked[objectStart:objectEnd], key)
+		if err != nil {
+			return newJsonparserError(path[:index+1], err)
+		}
+		if member == nil {
+			return d.insert(blanked, objectStart, objectEnd, path[index:], value)
+		}
+
+		valueStart := objectStart + member.offset
+		valueEnd := valueStart + len(member.value)
+		if member.dataType == jsonparser.String {
+			valueEnd += 2
+		}
+
+		if index == len(path)-1 {
+			encoded, err := d.mapping.encodeDocumentValue(value, lineIndent(d.data, valueStart), indentUnit(d.data), bytes.IndexByte(d.data, '\n') >= 0)
+			if err != nil {
+				return err
+			}
+			d.splice(edit{start: valueStart, end: valueEnd, text: encoded})
+			return nil
+		}
+
+		if member.dataType != jsonparser.Object {
+			return fmt.Errorf("value at '%s' is of type %s instead of object: %w", formatPath(path[:index+1]), member.dataType, ErrPathConflict)
+		}
+		objectStart, objectEnd = valueStart, valueEnd
+	}
+	return nil
+}
+
+// insert adds the remaining keys to the object, creating nested objects for
+// all but the last key.
+func (d *Document) insert(blanked []byte, objectStart, objectEnd int, keys []string, value any) error {
+	parentIndent := lineIndent(d.data, objectStart)
+	closing := objectEnd - 1
+	last := closing - 1
+	for last > objectStart && isWhitespace(blanked[last]) {
+		last--
+	}
+
+	// Empty objects are written on multiple lines, unless the whole document
+	// is written on a single line.
+	if last == objectStart {
+		multiline := bytes.IndexByte(d.data, '\n') >= 0
+		childIndent := parentIndent + indentUnit(d.data)
+		member, err := d.mapping.encodeDocumentMember(keys, value, childIndent, indentUnit(d.data), multiline)
+		if err != nil {
+			return err
+		}
+
+		inner := d.data[objectStart+1 : closing]
+		if !multiline {
+			if len(bytes.TrimSpace(inner)) == 0 {
+				d.splice(edit{start: objectStart + 1, end: closing, text: member})
+			} else {
+				d.splice(edit{start: objectStart + 1, end: objectStart + 1, text: member + " "})
+			}
+			return nil
+		}
+
+		if len(bytes.TrimSpace(inner)) == 0 {
+			d.splice(edit{start: objectStart + 1, end: closing, text: "\n" + childIndent + member + "\n" + parentIndent})
+			return nil
+		}
+		edits := []edit{{start: objectStart + 1, end: objectStart + 1, text: "\n" + childIndent + member}}
+		if bytes.IndexByte(blanked[objectStart+1:closing], '\n') < 0 {
+			edits = append(edits, edit{start: closing, end: closing, text: "\n" + parentIndent})
+		}
+		d.splice(edits...)
+		return nil
+	}
+
+	trailingComma := blanked[last] == ','
+	lastValueEnd := last + 1
+	if trailingComma {
+		lastValueEnd = last
+	}
+
+	// The whitespace preceding the last key defines the layout of the object.
+	var lastValueStart int
+	if err := jsonparser.ObjectEach(blanked[objectStart:objectEnd], func(_, value []byte, dataType jsonparser.ValueType, offset int) error {
+		lastValueStart = objectStart + offset - len(value)
+		if dataType == jsonparser.String {
+			lastValueStart -= 2
+		}
+		return nil
+	}); err != nil {
+		return newJsonparserError(nil, err)
+	}
+	keyStart := findKeyStart(blanked, lastValueStart)
+	separatorStart := keyStart
+	for separatorStart > objectStart+1 && isWhitespace(blanked[separatorStart-1]) {
+		separatorStart--
+	}
+	separator := string(blanked[separatorStart:keyStart])
+	multiline := strings.Contains(separator, "\n")
+	if separator == "" {
+		separator = " "
+	}
+
+	var childIndent string
+	if multiline {
+		childIndent = lineIndent(d.data, keyStart)
+	} else {
+		childIndent = parentIndent
+	}
+	member, err := d.mapping.encodeDocumentMember(keys, value, childIndent, indentUnit(d.data), multiline)
+	if err != nil {
+		return err
+	}
+
+	if !multiline {
+		if trailingComma {
+			d.splice(edit{start: last + 1, end: last + 1, text: separator + member + ","})
+		} else {
+			d.splice(edit{start: lastValueEnd, end: lastValueEnd, text: "," + separator + member})
+		}
+		return nil
+	}
+
+	// New members are added after comments following the last member on the
+	// same line, as these usually refer to the last member.
+	insertAt := last + 1
+	lineEnd := bytes.IndexAny(blanked[insertAt:closing], "\r\n")
+	if lineEnd >= 0 && len(bytes.TrimSpace(blanked[insertAt:insertAt+lineEnd])) == 0 {
+		insertAt += lineEnd
+	}
+	text := "\n" + childIndent + member
+	if trailingComma {
+		d.splice(edit{start: insertAt, end: insertAt, text: text + ","})
+		return nil
+	}
+	d.splice(
+		edit{start: lastValueEnd, end: lastValueEnd, text: ","},
+		edit{start: insertAt, end: insertAt, text: text},
+	)
+	return nil
+}
+
+// edit replaces the bytes between start and end with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// splice applies the edits, which have to be ordered by their position and
+// must not overlap.
+func (d *Document) splice(edits ...edit) {
+	for index := len(edits) - 1; index >= 0; index-- {
+		current := edits[index]
+		data := make([]byte, 0, len(d.data)-(current.end-current.start)+len(current.text))
+		data = append(data, d.data[:current.start]...)
+		data = append(data, current.text...)
+		data = append(data, d.data[current.end:]...)
+		d.data = data
+	}
+}
+
+// encodeDocumentMember encodes a key value pair. For all keys but the last
+// one, objects are created.
+func (m *mapping) encodeDocumentMember(keys []string, value any, indent, unit string, multiline bool) (string, error) {
+	key, err := marshalJSON(keys[0])
+	if err != nil {
+		return "", err
+	}
+	if len(keys) == 1 {
+		encoded, err := m.encodeDocumentValue(value, indent, unit, multiline)
+		return string(key) + ": " + encoded, err
+	}
+
+	if !multiline {
+		member, err := m.encodeDocumentMember(keys[1:], value, indent, unit, false)
+		return string(key) + ": {" + member + "}", err
+	}
+	member, err := m.encodeDocumentMember(keys[1:], value, indent+unit, unit, true)
+	return string(key) + ": {\n" + indent + unit + member + "\n" + indent + "}", err
+}
+
+// encodeDocumentValue encodes a value using the same rules as Marshal.
+func (m *mapping) encodeDocumentValue(value any, indent, unit string, multiline bool) (string, error) {
+	e := &encoder{mapping: m, omitEmpty: true, indent: unit}
+	reflectValue := reflect.ValueOf(value)
+	for reflectValue.Kind() == reflect.Pointer && !reflectValue.IsNil() {
+		reflectValue = reflectValue.Elem()
+	}
+
+	var err error
+	switch {
+	case !reflectValue.IsValid(), reflectValue.Kind() == reflect.Pointer:
+		e.buffer.WriteString("null")
+	case reflectValue.Kind() == reflect.Struct && !hasCustomMarshaler(reflectValue.Type()):
+		err = e.writeStruct(reflectValue, indent, nil)
+	default:
+		err = e.writeValue(reflectValue, false, indent)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if multiline {
+		return e.buffer.String(), nil
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, e.buffer.Bytes()); err != nil {
+		return "", err
+	}
+	return compacted.String(), nil
+}
+
+// findKeyStart returns the offset of the opening quote of the key belonging
+// to the value starting at valueStart.
+func findKeyStart(blanked []byte, valueStart int) int {
+	index := valueStart - 1
+	for isWhitespace(blanked[index]) || blanked[index] == ':' {
+		index--
+	}
+	// index is now at the closing quote of the key.
+	for index--; index > 0; index-- {
+		if blanked[index] != '"' {
+			continue
+		}
+		escapes := 0
+		for blanked[index-escapes-1] == '\\' {
+			escapes++
+		}
+		if escapes%2 == 0 {
+			break
+		}
+	}
+	return index
+}
+
+// lineIndent returns the whitespace at the beginning of the line containing
+// the given offset.
+func lineIndent(data []byte, offset int) string {
+	lineStart := bytes.LastIndexAny(data[:offset], "\r\n") + 1
+	lineEnd := lineStart
+	for lineEnd < len(data) && (data[lineEnd] == ' ' || data[lineEnd] == '\t') {
+		lineEnd++
+	}
+	return string(data[lineStart:lineEnd])
+}
+
+// indentUnit returns the indentation of the first indented line, which is
+// assumed to be a single level of indentation.
+func indentUnit(data []byte) string {
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if indent := lineIndent(line, 0); indent != "" && len(bytes.TrimSpace(line)) > 0 {
+			return indent
+		}
+	}
+	return encodeIndent
+}
+
+func isWhitespace(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+// FieldPath resolves a path of Go field names, such as "Database.Port", to
+// the path of JSON keys the fields are bound to, using the same rules as
+// Parse. The options should match the ones used for loading, see
+// MappingOption.
+func FieldPath[T any](fieldPath string, options ...MappingOption) ([]string, error) {
+	path, _, err := newMapping(options).fieldPath(reflect.TypeOf((*T)(nil)).Elem(), fieldPath)
+	return path, err
+}
+
+// fieldPath resolves the path of Go field names to the path of JSON keys and
+// the field at the end of the path.
+func (m *mapping) fieldPath(structType reflect.Type, fieldPath string) ([]string, *boundField, error) {
+	var path []string
+	var found *boundField
+	for _, name := range strings.Split(fieldPath, ".") {
+		structType = extractNonPointerFieldType(structType)
+		if structType.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("'%s' isn't a struct: %w", formatPath(path), ErrUnknownField)
+		}
+
+		fields, err := m.source.typeFields(m.companion, structType)
+		if err != nil {
+			return nil, nil, err
+		}
+		found = nil
+		for index := range fields {
+			if fields[index].structField.Name == name {
+				found = &fields[index]
+				break
+			}
+		}
+		if found == nil {
+			return nil, nil, fmt.Errorf("field '%s' in '%s': %w", name, fieldPath, ErrUnknownField)
+		}
+
+		path = append(path, found.key)
+		structType = found.structField.Type
+	}
+	return path, found, nil
+}
+
+// SetField sets the value of the key bound to the given field of T, where
+// fieldPath is a path of Go field names, such as "Database.Port". Keys are
+// resolved using the options the document has been parsed with. Just like
+// Marshal, values of fields with the `json:",string"` option are wrapped in
+// a JSON string.
+func SetField[T any](document *Document, fieldPath string, value any) error {
+	path, field, err := document.mapping.fieldPath(reflect.TypeOf((*T)(nil)).Elem(), fieldPath)
+	if err != nil {
+		return err
+	}
+
+	fieldType := extractNonPointerFieldType(field.structField.Type)
+	if reflectValue := reflect.ValueOf(value); field.tag.asString && supportsStringOption(fieldType) &&
+		reflectValue.IsValid() && !(reflectValue.Kind() == reflect.Pointer && reflectValue.IsNil()) {
+		encoded, err := marshalJSON(value)
+		if err != nil {
+			return err
+		}
+		value = string(encoded)
+	}
+	return document.Set(path, value)
+}
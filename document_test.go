@@ -0,0 +1,212 @@
+package yagcl_json
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+type documentConfiguration struct {
+	CommonConfig
+	Timeout  time.Duration `key:"timeout"`
+	Database struct {
+		Host string `key:"host"`
+		Port int    `key:"port"`
+	} `key:"database"`
+	Ignored string `key:"ignored" ignore:"true"`
+}
+
+const documentJSONC = `{
+    // The name of the service.
+    "name": "service", /* inline */
+    "database": {
+        "host": "localhost", // Local only!
+        "port": 5432
+    },
+    "extra": [1, 2,   3]
+}
+`
+
+func Test_Document_Replace(t *testing.T) {
+	document, err := ParseDocument([]byte(documentJSONC))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, SetField[documentConfiguration](document, "Database.Port", 5433))
+	assert.NoError(t, SetField[documentConfiguration](document, "Name", "other"))
+	assert.NoError(t, document.Set([]string{"extra"}, []int{4}))
+	assert.Equal(t, `{
+    // The name of the service.
+    "name": "other", /* inline */
+    "database": {
+        "host": "localhost", // Local only!
+        "port": 5433
+    },
+    "extra": [
+        4
+    ]
+}
+`, string(document.Bytes()))
+}
+
+func Test_Document_Insert(t *testing.T) {
+	document, err := ParseDocument([]byte(documentJSONC))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, SetField[documentConfiguration](document, "Timeout", 5*time.Second))
+	assert.NoError(t, document.Set([]string{"database", "options", "ssl"}, true))
+	assert.NoError(t, SetField[documentConfiguration](document, "Verbose", true))
+	assert.Equal(t, `{
+    // The name of the service.
+    "name": "service", /* inline */
+    "database": {
+        "host": "localhost", // Local only!
+        "port": 5432,
+        "options": {
+            "ssl": true
+        }
+    },
+    "extra": [1, 2,   3],
+    "timeout": "5s",
+    "verbose": true
+}
+`, string(document.Bytes()))
+
+	var c documentConfiguration
+	err = yagcl.New[documentConfiguration]().
+		Add(Source().Bytes(document.Bytes())).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5*time.Second, c.Timeout)
+		assert.Equal(t, 5432, c.Database.Port)
+		assert.True(t, c.Verbose)
+	}
+}
+
+func Test_Document_Insert_Layouts(t *testing.T) {
+	for _, value := range []struct {
+		name     string
+		document string
+		path     []string
+		value    any
+		expected string
+	}{
+		{"single line", `{"a": 1}`, []string{"b"}, 2, `{"a": 1, "b": 2}`},
+		{"single line nested", `{"a": 1}`, []string{"b", "c"}, []int{1, 2}, `{"a": 1, "b": {"c": [1,2]}}`},
+		{"single line empty", `{ }`, []string{"a"}, "x", `{"a": "x"}`},
+		{"single line trailing comma", `{"a": 1,}`, []string{"b"}, 2, `{"a": 1, "b": 2,}`},
+		{"trailing comma", "{\n\t\"a\": 1,\n}", []string{"b"}, 2, "{\n\t\"a\": 1,\n\t\"b\": 2,\n}"},
+		{"trailing comment", "{\n  \"a\": 1 // one\n}", []string{"b"}, 2, "{\n  \"a\": 1, // one\n  \"b\": 2\n}"},
+		{"empty", "{\n}", []string{"a"}, 1, "{\n  \"a\": 1\n}"},
+		{"empty nested", "{\n\t\"a\": {}\n}", []string{"a", "b"}, 1, "{\n\t\"a\": {\n\t\t\"b\": 1\n\t}\n}"},
+		{"empty with comment", "{\n\t\"a\": { /* none */ }\n}", []string{"a", "b"}, 1, "{\n\t\"a\": {\n\t\t\"b\": 1 /* none */ \n\t}\n}"},
+		{"escaped key", "{\n  \"a\\\"\": 1\n}", []string{"b"}, nil, "{\n  \"a\\\"\": 1,\n  \"b\": null\n}"},
+	} {
+		t.Run(value.name, func(t *testing.T) {
+			document, err := ParseDocument([]byte(value.document))
+			if assert.NoError(t, err) && assert.NoError(t, document.Set(value.path, value.value)) {
+				assert.Equal(t, value.expected, string(document.Bytes()))
+			}
+		})
+	}
+}
+
+func Test_Document_Errors(t *testing.T) {
+	_, err := ParseDocument([]byte(`[]`))
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+	_, err = ParseDocument([]byte(`{"a": }`))
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+
+	document, err := ParseDocument([]byte(`{"a": 1}`))
+	if assert.NoError(t, err) {
+		assert.ErrorIs(t, document.Set([]string{"a", "b"}, 1), ErrPathConflict)
+		assert.ErrorIs(t, document.Set(nil, 1), ErrPathConflict)
+	}
+}
+
+func Test_FieldPath(t *testing.T) {
+	path, err := FieldPath[documentConfiguration]("Database.Host")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"database", "host"}, path)
+	}
+
+	for _, fieldPath := range []string{"Ignored", "Missing", "Name.Other", "Database.Missing"} {
+		_, err := FieldPath[documentConfiguration](fieldPath)
+		assert.ErrorIs(t, err, ErrUnknownField, fieldPath)
+	}
+}
+
+func Test_FieldPath_Options(t *testing.T) {
+	type configuration struct {
+		Database struct {
+			MaxConnections int
+		}
+	}
+
+	path, err := FieldPath[configuration]("Database.MaxConnections", WithSource(Source().String(`{}`).KeyNaming(KebabCase)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"database", "max-connections"}, path)
+	}
+
+	path, err = FieldPath[configuration]("Database.MaxConnections", WithLoader(yagcl.New[configuration]().InferFieldKeys()))
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"database", "maxconnections"}, path)
+	}
+}
+
+func Test_Document_RoundTrip_StringOption(t *testing.T) {
+	type configuration struct {
+		Count   int   `json:"count,string"`
+		Enabled bool  `json:"enabled,string"`
+		Limit   *int  `json:"limit,string"`
+		Other   int64 `json:"other,string"`
+	}
+
+	document, err := ParseDocument([]byte("{\n  \"count\": \"1\"\n}\n"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	limit := 3
+	assert.NoError(t, SetField[configuration](document, "Count", 2))
+	assert.NoError(t, SetField[configuration](document, "Enabled", true))
+	assert.NoError(t, SetField[configuration](document, "Limit", &limit))
+	assert.NoError(t, SetField[configuration](document, "Other", nil))
+	assert.Equal(t, "{\n  \"count\": \"2\",\n  \"enabled\": \"true\",\n  \"limit\": \"3\",\n  \"other\": null\n}\n", string(document.Bytes()))
+
+	var c configuration
+	err = yagcl.New[configuration]().
+		Add(Source().Bytes(document.Bytes())).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, configuration{Count: 2, Enabled: true, Limit: &limit}, c)
+	}
+}
+
+func Test_Document_RoundTrip_KeyMatching(t *testing.T) {
+	type configuration struct {
+		ServerPort int
+		Host       string `key:"host"`
+	}
+
+	source := Source().String(`{}`).KeyNaming(SnakeCase).KeyMatching(KeyMatchCaseInsensitive)
+	document, err := ParseDocument([]byte(`{"Server_Port": 1, "HOST": "a"}`), WithSource(source))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, SetField[configuration](document, "ServerPort", 2))
+	assert.NoError(t, document.Set([]string{"host"}, "b"))
+	assert.Equal(t, `{"Server_Port": 2, "HOST": "b"}`, string(document.Bytes()))
+
+	var c configuration
+	err = yagcl.New[configuration]().
+		Add(Source().Bytes(document.Bytes()).KeyNaming(SnakeCase).KeyMatching(KeyMatchCaseInsensitive)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, configuration{ServerPort: 2, Host: "b"}, c)
+	}
+}
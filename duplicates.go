@@ -0,0 +1,253 @@
+package yagcl_json
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/buger/jsonparser"
+)
+
+// ErrDuplicateKey is wrapped by DuplicateKeyError.
+var ErrDuplicateKey = errors.New("duplicate key")
+
+// DuplicateKeyError is returned if an object contains the same key more than
+// once and DuplicateKeysError is used.
+type DuplicateKeyError struct {
+	// Path is the JSON path of the duplicate key. Array elements are
+	// identified by their index, for example "servers.0.port".
+	Path string
+	// FirstLine is the line of the first occurrence of the key.
+	FirstLine int
+	// SecondLine is the line of the second occurrence of the key.
+	SecondLine int
+}
+
+// Error implements error.Error.
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("key '%s' is defined in line %d and line %d: %s", e.Path, e.FirstLine, e.SecondLine, ErrDuplicateKey)
+}
+
+// Unwrap allows checking for ErrDuplicateKey using errors.Is.
+func (e *DuplicateKeyError) Unwrap() error {
+	return ErrDuplicateKey
+}
+
+// DuplicateKeyPolicy defines how objects containing the same key more than
+// once are treated. The policy applies to all objects of the document,
+// including objects that are decoded via encoding/json, such as objects
+// inside of slices and maps.
+type DuplicateKeyPolicy int
+
+const (
+	// DuplicateKeysFirstWins uses the first occurrence of a key. This is the
+	// default.
+	DuplicateKeysFirstWins DuplicateKeyPolicy = iota
+	// DuplicateKeysLastWins uses the last occurrence of a key, just like
+	// encoding/json does.
+	DuplicateKeysLastWins
+	// DuplicateKeysError causes parsing to fail with a DuplicateKeyError.
+	DuplicateKeysError
+)
+
+// member is the location of a single key value pair inside the document.
+type member struct {
+	key string
+	// keyStart is the offset of the key's opening quote.
+	keyStart int
+	// valueEnd is the offset of the comma or closing brace following the
+	// value. Only whitespace can be found between the value and valueEnd.
+	valueEnd int
+	// comma is the offset of the comma following the value or -1.
+	comma int
+}
+
+// openContainer is an object or array that hasn't been closed yet.
+type openContainer struct {
+	array bool
+	// name is the key or the index of the container inside of its parent.
+	name string
+	// elements is the number of commas seen in an array.
+	elements int
+	// expectKey is set at the start of objects and after commas.
+	expectKey bool
+	members   []member
+}
+
+// resolveDuplicateKeys removes all but one occurrence of each key in all
+// objects of the document, depending on the policy. Removed members are
+// replaced with whitespace, so that all offsets stay valid. The document is
+// only copied if it contains duplicates.
+//
+// The document is scanned once, as it might be untrusted. Invalid documents
+// are returned unchanged, their errors are reported once they are parsed.
+func (s *jsonSourceImpl) resolveDuplicateKeys(document []byte) ([]byte, error) {
+	resolver := &duplicateResolver{source: s, document: document}
+	var stack []*openContainer
+	for index := 0; index < len(document); index++ {
+		var top *openContainer
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		switch char := document[index]; char {
+		case ' ', '\t', '\n', '\r', ':':
+		case ',':
+			if top == nil {
+				return document, nil
+			}
+			if top.array {
+				top.elements++
+			} else if len(top.members) > 0 && !top.expectKey {
+				last := &top.members[len(top.members)-1]
+				last.valueEnd, last.comma = index, index
+				top.expectKey = true
+			}
+		case '{', '[':
+			var name string
+			if top != nil && top.array {
+				name = strconv.Itoa(top.elements)
+			} else if top != nil && len(top.members) > 0 {
+				name = top.members[len(top.members)-1].key
+			}
+			stack = append(stack, &openContainer{array: char == '[', name: name, expectKey: char == '{'})
+		case '}', ']':
+			if top == nil || top.array != (char == ']') {
+				return document, nil
+			}
+			if !top.array {
+				if len(top.members) > 0 && top.members[len(top.members)-1].comma < 0 {
+					top.members[len(top.members)-1].valueEnd = index
+				}
+				if err := resolver.resolveObject(stack, top.members); err != nil {
+					return nil, err
+				}
+			}
+			stack = stack[:len(stack)-1]
+		case '"':
+			start := index
+			escaped := false
+			for index++; index < len(document) && document[index] != '"'; index++ {
+				if document[index] == '\\' {
+					escaped = true
+					index++
+				}
+			}
+			if index >= len(document) {
+				return document, nil
+			}
+			if top == nil || top.array || !top.expectKey {
+				continue
+			}
+
+			// Keys are only unescaped if necessary.
+			key := string(document[start+1 : index])
+			if escaped {
+				unescaped, err := jsonparser.ParseString(document[start+1 : index])
+				if err != nil {
+					return document, nil
+				}
+				key = unescaped
+			}
+			top.members = append(top.members, member{key: key, keyStart: start, comma: -1})
+			top.expectKey = false
+		default:
+			// Numbers and literals such as true and null.
+			for index+1 < len(document) && !isWhitespace(document[index+1]) &&
+				document[index+1] != ',' && document[index+1] != ']' && document[index+1] != '}' {
+				index++
+			}
+		}
+	}
+	if len(stack) > 0 {
+		return document, nil
+	}
+
+	if resolver.result == nil {
+		return document, nil
+	}
+	return resolver.result, nil
+}
+
+type duplicateResolver struct {
+	source   *jsonSourceImpl
+	document []byte
+	// result is a lazily created copy of the document.
+	result []byte
+}
+
+// resolveObject removes the duplicates of the object on top of the stack.
+func (r *duplicateResolver) resolveObject(stack []*openContainer, members []member) error {
+	if len(members) < 2 {
+		return nil
+	}
+
+	// The indices of all occurrences of each key.
+	indices := make(map[string][]int, len(members))
+	var hasDuplicates bool
+	for index, current := range members {
+		indices[current.key] = append(indices[current.key], index)
+		hasDuplicates = hasDuplicates || len(indices[current.key]) > 1
+	}
+	if !hasDuplicates {
+		return nil
+	}
+
+	keep := make([]bool, len(members))
+	for index, current := range members {
+		occurrences := indices[current.key]
+		switch {
+		case len(occurrences) == 1:
+			keep[index] = true
+		case r.source.duplicateKeys == DuplicateKeysError:
+			jsonPath := make([]string, 0, len(stack))
+			// The root has no name.
+			for _, parent := range stack[1:] {
+				jsonPath = append(jsonPath, parent.name)
+			}
+			firstLine, _ := position(r.document, members[occurrences[0]].keyStart)
+			secondLine, _ := position(r.document, members[occurrences[1]].keyStart)
+			return &DuplicateKeyError{
+				Path:       formatPath(append(jsonPath, current.key)),
+				FirstLine:  firstLine,
+				SecondLine: secondLine,
+			}
+		case r.source.duplicateKeys == DuplicateKeysLastWins:
+			keep[index] = occurrences[len(occurrences)-1] == index
+		default:
+			keep[index] = occurrences[0] == index
+		}
+	}
+
+	if r.result == nil {
+		r.result = make([]byte, len(r.document))
+		copy(r.result, r.document)
+	}
+	// All commas are removed, then the ones separating the remaining members
+	// are restored.
+	lastKept := -1
+	for index, current := range members {
+		if current.comma >= 0 {
+			r.blank(current.comma, current.comma+1)
+		}
+		if !keep[index] {
+			r.blank(current.keyStart, current.valueEnd)
+			continue
+		}
+		if lastKept >= 0 {
+			r.result[members[lastKept].comma] = ','
+		}
+		lastKept = index
+	}
+	return nil
+}
+
+// blank replaces everything but line breaks between start and end with
+// spaces.
+func (r *duplicateResolver) blank(start, end int) {
+	for index := start; index < end; index++ {
+		if r.result[index] != '\n' && r.result[index] != '\r' {
+			r.result[index] = ' '
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package yagcl_json
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+type duplicatesConfiguration struct {
+	Port    int `key:"port"`
+	Servers []struct {
+		Port int `json:"port"`
+	} `key:"servers"`
+	Labels map[string]string `key:"labels"`
+}
+
+const duplicatesDocument = `{
+	"port": 80,
+	"servers": [{"port": 1, "port": 2, "port": 3}],
+	"labels": {"a": "first", "b": "b", "a": "last",},
+	"port": 8080
+}`
+
+func Test_Parse_DuplicateKeys_FirstWins(t *testing.T) {
+	var c duplicatesConfiguration
+	err := yagcl.New[duplicatesConfiguration]().
+		Add(Source().String(duplicatesDocument)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 80, c.Port)
+		assert.Equal(t, 1, c.Servers[0].Port)
+		assert.Equal(t, map[string]string{"a": "first", "b": "b"}, c.Labels)
+	}
+}
+
+func Test_Parse_DuplicateKeys_LastWins(t *testing.T) {
+	var c duplicatesConfiguration
+	var provenance Provenance
+	err := yagcl.New[duplicatesConfiguration]().
+		Add(Source().String(duplicatesDocument).
+			DuplicateKeys(DuplicateKeysLastWins).
+			TrackProvenance(&provenance)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 8080, c.Port)
+		assert.Equal(t, 3, c.Servers[0].Port)
+		assert.Equal(t, map[string]string{"a": "last", "b": "b"}, c.Labels)
+
+		// Offsets aren't affected by removing duplicates.
+		origin, ok := provenance.Lookup("port")
+		if assert.True(t, ok) {
+			assert.Equal(t, 5, origin.Line)
+			assert.Equal(t, 10, origin.Column)
+		}
+	}
+}
+
+func Test_Parse_DuplicateKeys_Error(t *testing.T) {
+	for _, value := range []struct {
+		document   string
+		path       string
+		firstLine  int
+		secondLine int
+	}{
+		{duplicatesDocument, "servers.0.port", 3, 3},
+		{"{\n\"port\": 1,\n\"port\": 2\n}", "port", 2, 3},
+		{"{\"labels\": {\"a\": 1,\n\"\\u0061\": 2}}", "labels.a", 1, 2},
+	} {
+		t.Run(value.path, func(t *testing.T) {
+			var c duplicatesConfiguration
+			err := yagcl.New[duplicatesConfiguration]().
+				Add(Source().String(value.document).DuplicateKeys(DuplicateKeysError)).
+				Parse(&c)
+
+			assert.ErrorIs(t, err, ErrDuplicateKey)
+			var duplicateErr *DuplicateKeyError
+			if assert.True(t, errors.As(err, &duplicateErr)) {
+				assert.Equal(t, value.path, duplicateErr.Path)
+				assert.Equal(t, value.firstLine, duplicateErr.FirstLine)
+				assert.Equal(t, value.secondLine, duplicateErr.SecondLine)
+			}
+		})
+	}
+}
+
+func Test_Parse_DuplicateKeys_None(t *testing.T) {
+	var c duplicatesConfiguration
+	err := yagcl.New[duplicatesConfiguration]().
+		Add(Source().String(`{"port": 1, "servers": [{"port": 2}, {"port": 3}]}`).DuplicateKeys(DuplicateKeysError)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, c.Port)
+		assert.Len(t, c.Servers, 2)
+	}
+}
+
+func Test_resolveDuplicateKeys(t *testing.T) {
+	for _, value := range []struct {
+		policy   DuplicateKeyPolicy
+		document string
+		expected string
+	}{
+		{DuplicateKeysFirstWins, `{"a":1,"a":2,"a":3}`, `{"a":1            }`},
+		{DuplicateKeysLastWins, `{"a":1,"a":2,"a":3}`, `{            "a":3}`},
+		{DuplicateKeysLastWins, `{"a":1,"b":2,"a":3,}`, `{      "b":2,"a":3 }`},
+		{DuplicateKeysFirstWins, "{\"a\": {\n},\n\"a\": [\n]}", "{\"a\": {\n} \n      \n }"},
+		{DuplicateKeysFirstWins, `[{"a":1,"a":2}]`, `[{"a":1      }]`},
+	} {
+		source := &jsonSourceImpl{duplicateKeys: value.policy}
+		result, err := source.resolveDuplicateKeys([]byte(value.document))
+		if assert.NoError(t, err) {
+			assert.Equal(t, value.expected, string(result), value.document)
+		}
+	}
+}
+
+func Test_resolveDuplicateKeys_LargeInput(t *testing.T) {
+	// Deeply nested documents used to take time quadratic in their size.
+	const depth = 20000
+	document := `{"port": 1, "nested": ` + strings.Repeat("[", depth) + `{"a": 1, "a": 2}` +
+		strings.Repeat("]", depth) + `, "port": 2}`
+
+	source := &jsonSourceImpl{duplicateKeys: DuplicateKeysFirstWins}
+	start := time.Now()
+	result, err := source.resolveDuplicateKeys([]byte(document))
+	if assert.NoError(t, err) {
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Contains(t, string(result), `{"a": 1        }`)
+		assert.True(t, strings.HasSuffix(string(result), `]           }`))
+	}
+
+	source.duplicateKeys = DuplicateKeysError
+	_, err = source.resolveDuplicateKeys([]byte(document))
+	var duplicateErr *DuplicateKeyError
+	if assert.True(t, errors.As(err, &duplicateErr)) {
+		assert.Equal(t, "nested"+strings.Repeat(".0", depth)+".a", duplicateErr.Path)
+	}
+}
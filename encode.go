@@ -0,0 +1,229 @@
+package yagcl_json
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/buger/jsonparser"
+)
+
+const encodeIndent = "  "
+
+// encoder writes configuration structs as indented JSON. Keys are resolved
+// the same way the JSON source resolves them, so that the output can be
+// parsed again.
+type encoder struct {
+	*mapping
+	// comments enables writing the `description` tag of fields as `//`
+	// comments, turning the output into JSONC.
+	comments bool
+	// defaults enables writing the `default` tag of fields holding their
+	// zero value. Nil struct pointers are written as empty structs, so that
+	// all of their keys are visible.
+	defaults bool
+	// omitEmpty enables skipping fields with the `json:",omitempty"` option
+	// if they hold an empty value. Fields with a `default` tag are never
+	// skipped, as the default would be applied when loading the output.
+	omitEmpty bool
+	// redact enables replacing the values of secret fields with Redacted.
+	redact bool
+	// indent is a single level of indentation.
+	indent string
+
+	buffer bytes.Buffer
+}
+
+func newEncoder(options []MappingOption) *encoder {
+	return &encoder{
+		mapping: newMapping(options),
+		indent:  encodeIndent,
+	}
+}
+
+func (e *encoder) encode(configuration any) ([]byte, error) {
+	value := reflect.ValueOf(configuration)
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("configuration has to be a struct, but was '%s': %w", value.Kind(), yagcl.ErrUnsupportedFieldType)
+	}
+
+	if err := e.writeStruct(value, "", nil); err != nil {
+		return nil, err
+	}
+	e.buffer.WriteByte('\n')
+	return e.buffer.Bytes(), nil
+}
+
+func (e *encoder) writeStruct(structValue reflect.Value, indent string, parentJsonPath []string) error {
+	fields, err := e.source.typeFields(e.companion, structValue.Type())
+	if err != nil {
+		return err
+	}
+	fieldIndent := indent + e.indent
+	var written int
+	e.buffer.WriteByte('{')
+	for _, field := range fields {
+		fieldValue, reachable := fieldByIndex(structValue, field.index)
+		if !reachable {
+			fieldValue = reflect.Zero(field.structField.Type)
+		}
+		if e.omitEmpty && field.tag.omitEmpty && isEmptyValue(fieldValue) && !hasDefault(field.structField) {
+			continue
+		}
+
+		if written > 0 {
+			e.buffer.WriteByte(',')
+		}
+		written++
+		e.buffer.WriteByte('\n')
+		if description := field.structField.Tag.Get("description"); e.comments && description != "" {
+			for _, line := range strings.Split(description, "\n") {
+				e.buffer.WriteString(strings.TrimRight(fieldIndent+"// "+line, " "))
+				e.buffer.WriteByte('\n')
+			}
+		}
+
+		key, err := marshalJSON(field.key)
+		if err != nil {
+			return err
+		}
+		e.buffer.WriteString(fieldIndent)
+		e.buffer.Write(key)
+		e.buffer.WriteString(": ")
+		jsonPath := append(parentJsonPath[:len(parentJsonPath):len(parentJsonPath)], field.key)
+		if err := e.writeField(field, fieldValue, fieldIndent, jsonPath); err != nil {
+			return fmt.Errorf("field '%s': %w", field.structField.Name, err)
+		}
+	}
+	if written > 0 {
+		e.buffer.WriteByte('\n')
+		e.buffer.WriteString(indent)
+	}
+	e.buffer.WriteByte('}')
+	return nil
+}
+
+func (e *encoder) writeField(field boundField, fieldValue reflect.Value, indent string, jsonPath []string) error {
+	fieldType := extractNonPointerFieldType(field.structField.Type)
+	asString := field.tag.asString && supportsStringOption(fieldType)
+
+	if e.redact && (isSecret(field.structField) || e.isEncrypted(jsonPath)) && !fieldValue.IsZero() {
+		e.buffer.WriteString(`"` + Redacted + `"`)
+		return nil
+	}
+
+	if literal, hasDefault := field.structField.Tag.Lookup("default"); e.defaults && hasDefault && fieldValue.IsZero() {
+		value, dataType := parseDefaultLiteral(literal, fieldType)
+		if dataType == jsonparser.String {
+			value = []byte(`"` + string(value) + `"`)
+		}
+		// Defaults aren't wrapped in a string, but values in the document
+		// are, just like in writeValue.
+		if asString {
+			var err error
+			if value, err = marshalJSON(string(value)); err != nil {
+				return err
+			}
+		}
+		return json.Indent(&e.buffer, value, indent, e.indent)
+	}
+
+	for fieldValue.Kind() == reflect.Pointer {
+		if fieldValue.IsNil() {
+			if e.defaults && fieldType.Kind() == reflect.Struct && !hasCustomMarshaler(fieldType) {
+				fieldValue = reflect.Zero(fieldType)
+				break
+			}
+			e.buffer.WriteString("null")
+			return nil
+		}
+		fieldValue = fieldValue.Elem()
+	}
+
+	if fieldType.Kind() == reflect.Struct && !hasCustomMarshaler(fieldType) {
+		return e.writeStruct(fieldValue, indent, jsonPath)
+	}
+	return e.writeValue(fieldValue, asString, indent)
+}
+
+// writeValue writes values that aren't bound to keys, using encoding/json,
+// just like the JSON source uses encoding/json to decode them.
+func (e *encoder) writeValue(value reflect.Value, asString bool, indent string) error {
+	var data []byte
+	var err error
+	if value.Type() == durationType {
+		data, err = marshalJSON(value.Interface().(time.Duration).String())
+	} else {
+		// Methods with pointer receivers are only visible via a pointer.
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		data, err = marshalJSON(pointer.Interface())
+	}
+	if err != nil {
+		return err
+	}
+
+	if asString {
+		if data, err = marshalJSON(string(data)); err != nil {
+			return err
+		}
+	}
+	return json.Indent(&e.buffer, data, indent, e.indent)
+}
+
+// hasDefault checks whether the field defines a `default` tag.
+func hasDefault(structField reflect.StructField) bool {
+	_, ok := structField.Tag.Lookup("default")
+	return ok
+}
+
+// isEmptyValue defines which values are omitted due to the omitempty option.
+// The definition is the same as encoding/json's.
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return value.IsNil()
+	}
+	return false
+}
+
+// marshalJSON is json.Marshal without escaping HTML characters.
+func marshalJSON(value any) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// hasCustomMarshaler checks whether the type implements json.Marshaler or
+// encoding.TextMarshaler.
+func hasCustomMarshaler(valueType reflect.Type) bool {
+	pointerType := reflect.PointerTo(valueType)
+	return pointerType.Implements(jsonMarshalerType) || pointerType.Implements(textMarshalerType)
+}
@@ -0,0 +1,208 @@
+package yagcl_json
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrDecryptionFailed is returned if an encrypted document or value can't
+// be decrypted.
+var ErrDecryptionFailed = errors.New("decryption failed")
+
+// ErrNotEncrypted is returned if a document isn't encrypted, but
+// JSONSourceOptionalSetup.RequireEncrypted has been used.
+var ErrNotEncrypted = errors.New("document isn't encrypted")
+
+// Decrypter turns ciphertext into plaintext.
+type Decrypter interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// decrypt decrypts the document, unless it already is a JSON object. In
+// either case, all string values of the form "ENC[<base64 ciphertext>]"
+// are replaced with their decrypted value afterwards, returning the offsets
+// of the replaced values as well. The document isn't decoded, as the limits
+// haven't been checked yet.
+func (s *jsonSourceImpl) decrypt(ctx context.Context, document []byte) ([]byte, map[int]bool, error) {
+	if !isPlainDocument(document) {
+		if ctx.Err() != nil {
+			return nil, nil, s.contextError(ctx)
+		}
+		plaintext, err := s.decrypter.Decrypt(document)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error decrypting source '%s' (%s): %w", s.sourceName(), err, ErrDecryptionFailed)
+		}
+		if ctx.Err() != nil {
+			return nil, nil, s.contextError(ctx)
+		}
+		document = plaintext
+	} else if s.requireEncrypted {
+		return nil, nil, fmt.Errorf("source '%s' contains plaintext: %w", s.sourceName(), ErrNotEncrypted)
+	}
+	return s.decryptValues(ctx, blankComments(document))
+}
+
+// isPlainDocument checks whether the document is a JSON object, as opposed to
+// ciphertext. Instead of decoding the document, only the first character is
+// checked. As ciphertext might start with a brace by chance, the document
+// also has to be valid UTF-8 without control characters other than
+// whitespace, which is very unlikely for ciphertext.
+func isPlainDocument(document []byte) bool {
+	trimmed := bytes.TrimSpace(document)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return false
+	}
+	for _, char := range trimmed {
+		if (char < ' ' && !isWhitespace(char)) || char == 0x7f {
+			return false
+		}
+	}
+	return utf8.Valid(trimmed)
+}
+
+// decryptValues replaces all strings of the form "ENC[<base64 ciphertext>]"
+// with their decrypted value. As the length of the values changes, offsets
+// following the values on the same line are shifted, therefore the offsets
+// of the decrypted values in the result are returned. The document must not
+// contain comments.
+func (s *jsonSourceImpl) decryptValues(ctx context.Context, document []byte) ([]byte, map[int]bool, error) {
+	if !bytes.Contains(document, []byte(`"ENC[`)) {
+		return document, nil, nil
+	}
+
+	var result []byte
+	offsets := make(map[int]bool)
+	var copied int
+	for index := 0; index < len(document); index++ {
+		if document[index] != '"' {
+			continue
+		}
+		start := index
+		for index++; index < len(document) && document[index] != '"'; index++ {
+			if document[index] == '\\' {
+				index++
+			}
+		}
+		if index >= len(document) {
+			break
+		}
+
+		content := string(document[start+1 : index])
+		if !strings.HasPrefix(content, "ENC[") || !strings.HasSuffix(content, "]") {
+			continue
+		}
+
+		line, column := position(document, start)
+		ciphertext, err := base64.StdEncoding.DecodeString(content[len("ENC[") : len(content)-1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("encrypted value at %d:%d isn't valid base64 (%s): %w", line, column, err, ErrDecryptionFailed)
+		}
+		if ctx.Err() != nil {
+			return nil, nil, s.contextError(ctx)
+		}
+		plaintext, err := s.decrypter.Decrypt(ciphertext)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error decrypting value at %d:%d (%s): %w", line, column, err, ErrDecryptionFailed)
+		}
+		encoded, err := marshalJSON(string(plaintext))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		result = append(result, document[copied:start]...)
+		offsets[len(result)] = true
+		result = append(result, encoded...)
+		copied = index + 1
+	}
+	if ctx.Err() != nil {
+		return nil, nil, s.contextError(ctx)
+	}
+	return append(result, document[copied:]...), offsets, nil
+}
+
+// AESGCM encrypts and decrypts data using AES in Galois/Counter Mode. The
+// ciphertext is prefixed with the randomly generated nonce.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM creates an AESGCM using a key of 16, 24 or 32 bytes, choosing
+// AES-128, AES-192 or AES-256.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// AESGCMFromEnv creates an AESGCM using the base64 encoded key found in the
+// given environment variable.
+func AESGCMFromEnv(name string) (*AESGCM, error) {
+	encoded, set := os.LookupEnv(name)
+	if !set {
+		return nil, fmt.Errorf("environment variable '%s' containing the key isn't set", name)
+	}
+	return newAESGCMFromBase64(encoded)
+}
+
+// AESGCMFromFile creates an AESGCM using the base64 encoded key stored in
+// the given file. Surrounding whitespace is ignored.
+func AESGCMFromFile(path string) (*AESGCM, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newAESGCMFromBase64(string(encoded))
+}
+
+func newAESGCMFromBase64(encoded string) (*AESGCM, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("key isn't valid base64: %w", err)
+	}
+	return NewAESGCM(key)
+}
+
+// Decrypt implements Decrypter.Decrypt.
+func (a *AESGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	nonceSize := a.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("ciphertext is too short")
+	}
+	return a.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], nil)
+}
+
+// Encrypt encrypts the plaintext, so that it can be decrypted using Decrypt.
+func (a *AESGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return a.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// EncryptValue encrypts the plaintext and returns it in the form
+// "ENC[<base64 ciphertext>]", which can be used as a string value in a
+// document read using JSONSourceOptionalSetup.Decrypt.
+func (a *AESGCM) EncryptValue(plaintext string) (string, error) {
+	ciphertext, err := a.Encrypt([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return "ENC[" + base64.StdEncoding.EncodeToString(ciphertext) + "]", nil
+}
@@ -0,0 +1,230 @@
+package yagcl_json
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEncryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+type encryptionConfiguration struct {
+	User     string `key:"user"`
+	Password string `key:"password"`
+	Port     int    `json:"port,string"`
+}
+
+func Test_Parse_Decrypt_Document(t *testing.T) {
+	aesgcm, err := NewAESGCM(testEncryptionKey)
+	require.NoError(t, err)
+	ciphertext, err := aesgcm.Encrypt([]byte(`{
+		// Comments are fine.
+		"user": "admin",
+		"password": "secret"
+	}`))
+	require.NoError(t, err)
+
+	var c encryptionConfiguration
+	err = yagcl.New[encryptionConfiguration]().
+		Add(Source().Bytes(ciphertext).Decrypt(aesgcm)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "admin", c.User)
+		assert.Equal(t, "secret", c.Password)
+	}
+}
+
+func Test_Parse_Decrypt_Values(t *testing.T) {
+	aesgcm, err := NewAESGCM(testEncryptionKey)
+	require.NoError(t, err)
+	password, err := aesgcm.EncryptValue(`"quoted"`)
+	require.NoError(t, err)
+	port, err := aesgcm.EncryptValue("5432")
+	require.NoError(t, err)
+
+	document := `{
+		// "ENC[invalid]" in comments is ignored.
+		"user": "admin",
+		"password": "` + password + `",
+		"port": "` + port + `"
+	}`
+
+	var c encryptionConfiguration
+	err = yagcl.New[encryptionConfiguration]().
+		Add(Source().String(document).Decrypt(aesgcm)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "admin", c.User)
+		assert.Equal(t, `"quoted"`, c.Password)
+		assert.Equal(t, 5432, c.Port)
+	}
+
+	// Without decrypting, the encrypted values are used as they are.
+	err = yagcl.New[encryptionConfiguration]().
+		Add(Source().String(`{"password": "` + password + `"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, password, c.Password)
+	}
+}
+
+func Test_Parse_Decrypt_Errors(t *testing.T) {
+	aesgcm, err := NewAESGCM(testEncryptionKey)
+	require.NoError(t, err)
+	other, err := NewAESGCM([]byte("fedcba9876543210"))
+	require.NoError(t, err)
+	value, err := other.EncryptValue("secret")
+	require.NoError(t, err)
+	document, err := other.Encrypt([]byte(`{}`))
+	require.NoError(t, err)
+
+	for _, value := range []struct {
+		name     string
+		document string
+		message  string
+	}{
+		{"wrong key", "{\n\"password\": \"" + value + "\"}", "error decrypting value at 2:13"},
+		{"invalid base64", `{"password": "ENC[!]"}`, "encrypted value at 1:14 isn't valid base64"},
+		{"document", string(document), "error decrypting source '<bytes>'"},
+		{"too short", "x", "ciphertext is too short"},
+	} {
+		t.Run(value.name, func(t *testing.T) {
+			var c encryptionConfiguration
+			err := yagcl.New[encryptionConfiguration]().
+				Add(Source().String(value.document).Decrypt(aesgcm)).
+				Parse(&c)
+			assert.ErrorIs(t, err, ErrDecryptionFailed)
+			assert.ErrorContains(t, err, value.message)
+		})
+	}
+}
+
+func Test_Parse_Decrypt_RequireEncrypted(t *testing.T) {
+	aesgcm, err := NewAESGCM(testEncryptionKey)
+	require.NoError(t, err)
+	ciphertext, err := aesgcm.Encrypt([]byte(`{"user": "admin"}`))
+	require.NoError(t, err)
+
+	var c encryptionConfiguration
+	err = yagcl.New[encryptionConfiguration]().
+		Add(Source().Bytes(ciphertext).Decrypt(aesgcm).RequireEncrypted()).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "admin", c.User)
+	}
+
+	err = yagcl.New[encryptionConfiguration]().
+		Add(Source().String(`{"user": "admin"}`).Decrypt(aesgcm).RequireEncrypted()).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrNotEncrypted)
+}
+
+func Test_Parse_Decrypt_CiphertextStartingWithBrace(t *testing.T) {
+	aesgcm, err := NewAESGCM(testEncryptionKey)
+	require.NoError(t, err)
+
+	// The nonce is random, so roughly every 256th ciphertext starts with a
+	// brace.
+	var ciphertext []byte
+	for ciphertext == nil || ciphertext[0] != '{' {
+		ciphertext, err = aesgcm.Encrypt([]byte(`{"user": "admin"}`))
+		require.NoError(t, err)
+	}
+
+	var c encryptionConfiguration
+	err = yagcl.New[encryptionConfiguration]().
+		Add(Source().Bytes(ciphertext).Decrypt(aesgcm)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "admin", c.User)
+	}
+}
+
+func Test_Parse_Decrypt_Limits(t *testing.T) {
+	aesgcm, err := NewAESGCM(testEncryptionKey)
+	require.NoError(t, err)
+
+	// Detecting plaintext documents must not decode them before the limits
+	// have been checked.
+	document := `{"user": ` + strings.Repeat("[", 100) + strings.Repeat("]", 100) + `}`
+	var c encryptionConfiguration
+	err = yagcl.New[encryptionConfiguration]().
+		Add(Source().String(document).Decrypt(aesgcm).MaxDepth(10)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+}
+
+// cancellingDecrypter cancels the context while decrypting.
+type cancellingDecrypter struct {
+	Decrypter
+	cancel context.CancelFunc
+}
+
+func (d cancellingDecrypter) Decrypt(ciphertext []byte) ([]byte, error) {
+	d.cancel()
+	return d.Decrypter.Decrypt(ciphertext)
+}
+
+func Test_Parse_Decrypt_Context(t *testing.T) {
+	aesgcm, err := NewAESGCM(testEncryptionKey)
+	require.NoError(t, err)
+	ciphertext, err := aesgcm.Encrypt([]byte(`{"user": "admin"}`))
+	require.NoError(t, err)
+	password, err := aesgcm.EncryptValue("secret")
+	require.NoError(t, err)
+
+	for name, document := range map[string][]byte{
+		"document": ciphertext,
+		"value":    []byte(`{"password": "` + password + `"}`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var c encryptionConfiguration
+			err := yagcl.New[encryptionConfiguration]().
+				Add(Source().Bytes(document).
+					Decrypt(cancellingDecrypter{Decrypter: aesgcm, cancel: cancel}).
+					Context(ctx)).
+				Parse(&c)
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
+
+func Test_AESGCM_Keys(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testEncryptionKey)
+
+	t.Setenv("YAGCL_TEST_KEY", encoded)
+	fromEnv, err := AESGCMFromEnv("YAGCL_TEST_KEY")
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(path, []byte(encoded+"\n"), 0o600))
+	fromFile, err := AESGCMFromFile(path)
+	require.NoError(t, err)
+
+	ciphertext, err := fromEnv.Encrypt([]byte("plaintext"))
+	require.NoError(t, err)
+	plaintext, err := fromFile.Decrypt(ciphertext)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "plaintext", string(plaintext))
+	}
+
+	_, err = AESGCMFromEnv("YAGCL_TEST_KEY_MISSING")
+	assert.ErrorContains(t, err, "YAGCL_TEST_KEY_MISSING")
+	_, err = AESGCMFromFile(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	t.Setenv("YAGCL_TEST_KEY", "!")
+	_, err = AESGCMFromEnv("YAGCL_TEST_KEY")
+	assert.ErrorContains(t, err, "base64")
+	_, err = NewAESGCM([]byte(strings.Repeat("a", 10)))
+	assert.Error(t, err)
+}
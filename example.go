@@ -0,0 +1,30 @@
+package yagcl_json
+
+// ExampleFormat defines the format of the files written by GenerateExample.
+type ExampleFormat int
+
+const (
+	// ExampleJSON produces plain JSON.
+	ExampleJSON ExampleFormat = iota
+	// ExampleJSONC produces JSON, where each key is preceded by the
+	// `description` tag of its field, written as `//` comments. The JSON
+	// source accepts these comments.
+	ExampleJSONC
+)
+
+// GenerateExample renders a configuration file for the given configuration.
+// Fields holding their zero value are written using their `default` tag, if
+// present. Nil struct pointers are expanded, so that all keys are visible.
+// Passing nil renders the zero value of T. Keys are resolved by the same
+// rules Parse applies, therefore ignored fields aren't written. The options
+// should match the ones used for loading, see MappingOption.
+func GenerateExample[T any](configuration *T, format ExampleFormat, options ...MappingOption) ([]byte, error) {
+	if configuration == nil {
+		configuration = new(T)
+	}
+
+	encoder := newEncoder(options)
+	encoder.defaults = true
+	encoder.comments = format == ExampleJSONC
+	return encoder.encode(configuration)
+}
@@ -0,0 +1,158 @@
+package yagcl_json
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+type exampleDatabase struct {
+	Host string `key:"host" description:"Hostname of the database server." default:"localhost"`
+	Port int    `key:"port" default:"5432"`
+}
+
+type exampleConfiguration struct {
+	CommonConfig
+	Timeout  time.Duration    `key:"timeout" description:"Request timeout,\nfor example \"30s\"." default:"30s"`
+	Level    string           `key:"level" default:"info"`
+	Hosts    []string         `key:"hosts" default:"[\"a\",\"b\"]"`
+	Count    int              `json:"count,string"`
+	Database *exampleDatabase `key:"database"`
+	Ignored  string           `key:"ignored" ignore:"true"`
+	Skipped  string           `json:"-"`
+}
+
+func Test_GenerateExample_JSON(t *testing.T) {
+	example, err := GenerateExample[exampleConfiguration](nil, ExampleJSON)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{
+  "name": "",
+  "verbose": false,
+  "timeout": "30s",
+  "level": "info",
+  "hosts": [
+    "a",
+    "b"
+  ],
+  "count": "0",
+  "database": {
+    "host": "localhost",
+    "port": 5432
+  }
+}
+`, string(example))
+	}
+}
+
+func Test_GenerateExample_JSONC(t *testing.T) {
+	c := exampleConfiguration{
+		Level: "debug",
+		Hosts: []string{"x"},
+		Count: 3,
+	}
+	c.Name = "service"
+
+	example, err := GenerateExample(&c, ExampleJSONC)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{
+  "name": "service",
+  "verbose": false,
+  // Request timeout,
+  // for example "30s".
+  "timeout": "30s",
+  "level": "debug",
+  "hosts": [
+    "x"
+  ],
+  "count": "3",
+  "database": {
+    // Hostname of the database server.
+    "host": "localhost",
+    "port": 5432
+  }
+}
+`, string(example))
+	}
+}
+
+func Test_GenerateExample_Parsable(t *testing.T) {
+	example, err := GenerateExample[exampleConfiguration](nil, ExampleJSONC)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var c exampleConfiguration
+	err = yagcl.New[exampleConfiguration]().
+		Add(Source().Bytes(example)).
+		Parse(&c)
+	if assert.NoError(t, err) && assert.NotNil(t, c.Database) {
+		assert.Equal(t, 30*time.Second, c.Timeout)
+		assert.Equal(t, []string{"a", "b"}, c.Hosts)
+		assert.Equal(t, "localhost", c.Database.Host)
+		assert.Equal(t, 5432, c.Database.Port)
+	}
+}
+
+func Test_GenerateExample_StringOption(t *testing.T) {
+	type configuration struct {
+		Retries int    `json:"retries,string" default:"3"`
+		Mode    string `json:"mode,string" default:"fast"`
+	}
+
+	example, err := GenerateExample[configuration](nil, ExampleJSON)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, `{
+  "retries": "3",
+  "mode": "\"fast\""
+}
+`, string(example))
+
+	var c configuration
+	err = yagcl.New[configuration]().
+		Add(Source().Bytes(example)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, configuration{Retries: 3, Mode: "fast"}, c)
+	}
+}
+
+func Test_GenerateExample_Empty(t *testing.T) {
+	type configuration struct {
+		Empty struct{} `key:"empty"`
+	}
+
+	example, err := GenerateExample[configuration](nil, ExampleJSON)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "{\n  \"empty\": {}\n}\n", string(example))
+	}
+}
+
+func Test_GenerateExample_MissingKey(t *testing.T) {
+	type configuration struct {
+		Field string
+	}
+
+	_, err := GenerateExample[configuration](nil, ExampleJSON)
+	assert.ErrorIs(t, err, yagcl.ErrExportedFieldMissingKey)
+}
+
+func Test_GenerateExample_Options(t *testing.T) {
+	type configuration struct {
+		ServerPort int `default:"8080"`
+		LogLevel   string
+	}
+
+	example, err := GenerateExample[configuration](nil, ExampleJSON, WithSource(Source().String(`{}`).KeyNaming(KebabCase)))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "{\n  \"server-port\": 8080,\n  \"log-level\": \"\"\n}\n", string(example))
+	}
+
+	example, err = GenerateExample[configuration](nil, ExampleJSON, WithLoader(yagcl.New[configuration]().InferFieldKeys()))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "{\n  \"serverport\": 8080,\n  \"loglevel\": \"\"\n}\n", string(example))
+	}
+}
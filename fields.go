@@ -0,0 +1,209 @@
+package yagcl_json
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// boundField is a struct field that is bound to a JSON key. The field might
+// be a promoted field of an embedded struct.
+type boundField struct {
+	structField reflect.StructField
+	// index is the index sequence for reflect.Value.FieldByIndex.
+	index []int
+	key   string
+	tag   jsonTag
+	// tagged defines whether the key has been defined explicitly.
+	tagged bool
+}
+
+// typeFields returns all fields of the given struct type that are bound to
+// a JSON key. Just like encoding/json, fields of embedded structs are
+// promoted, unless the embedded field defines a key itself. The same is
+// the case for fields with the option `json:",inline"`. If multiple fields
+// share the same key, the least nested one wins. If there are multiple
+// fields on the same level, a field with an explicit key wins. If that still
+// doesn't resolve the conflict, all of the fields are ignored.
+func (s *jsonSourceImpl) typeFields(parsingCompanion yagcl.ParsingCompanion, structType reflect.Type) ([]boundField, error) {
+	type embeddedStruct struct {
+		structType reflect.Type
+		index      []int
+	}
+
+	var fields []boundField
+	next := []embeddedStruct{{structType: structType}}
+	visited := make(map[reflect.Type]bool)
+	for len(next) > 0 {
+		current := next
+		next = nil
+		for _, embedded := range current {
+			// Recursive embedding is only relevant on the first level it
+			// occurs, any deeper fields would be hidden anyway.
+			if visited[embedded.structType] {
+				continue
+			}
+			visited[embedded.structType] = true
+
+			for i := 0; i < embedded.structType.NumField(); i++ {
+				structField := embedded.structType.Field(i)
+				index := append(embedded.index[:len(embedded.index):len(embedded.index)], i)
+				tag := parseJSONTag(structField.Tag.Get(s.KeyTag()))
+				if tag.skip {
+					continue
+				}
+
+				promoted, err := s.isPromoted(parsingCompanion, structField, tag)
+				if err != nil {
+					return nil, err
+				}
+				if promoted {
+					next = append(next, embeddedStruct{
+						structType: extractNonPointerFieldType(structField.Type),
+						index:      index,
+					})
+					continue
+				}
+
+				// By default, all exported fiels are not ignored and all exported
+				// fields are. Unexported fields can't be un-ignored though.
+				if !parsingCompanion.IncludeField(structField) {
+					continue
+				}
+
+				key, err := s.extractJSONKey(parsingCompanion, structField)
+				if err != nil {
+					return nil, err
+				}
+				fields = append(fields, boundField{
+					structField: structField,
+					index:       index,
+					key:         key,
+					tag:         tag,
+					tagged:      hasExplicitKey(parsingCompanion, structField, tag),
+				})
+			}
+		}
+	}
+
+	// Since the fields are ordered by depth already, we only have to put
+	// explicitly tagged fields first.
+	sort.SliceStable(fields, func(a, b int) bool {
+		if fields[a].key != fields[b].key {
+			return fields[a].key < fields[b].key
+		}
+		if len(fields[a].index) != len(fields[b].index) {
+			return len(fields[a].index) < len(fields[b].index)
+		}
+		return fields[a].tagged && !fields[b].tagged
+	})
+
+	dominantFields := fields[:0]
+	for start := 0; start < len(fields); {
+		end := start + 1
+		for end < len(fields) && fields[end].key == fields[start].key {
+			end++
+		}
+
+		if end-start == 1 ||
+			len(fields[start].index) != len(fields[start+1].index) ||
+			fields[start].tagged != fields[start+1].tagged {
+			dominantFields = append(dominantFields, fields[start])
+		}
+		start = end
+	}
+
+	// Restore the declaration order, so errors are deterministic.
+	sort.Slice(dominantFields, func(a, b int) bool {
+		indexA, indexB := dominantFields[a].index, dominantFields[b].index
+		for i := 0; i < len(indexA) && i < len(indexB); i++ {
+			if indexA[i] != indexB[i] {
+				return indexA[i] < indexB[i]
+			}
+		}
+		return len(indexA) < len(indexB)
+	})
+	return dominantFields, nil
+}
+
+// isPromoted checks whether the fields of the given struct field have to be
+// read from the object containing the field itself.
+func (s *jsonSourceImpl) isPromoted(parsingCompanion yagcl.ParsingCompanion, structField reflect.StructField, tag jsonTag) (bool, error) {
+	fieldType := extractNonPointerFieldType(structField.Type)
+	if tag.inline {
+		if fieldType.Kind() != reflect.Struct {
+			return false, fmt.Errorf("field '%s' can't be inlined, as it isn't a struct: %w", structField.Name, yagcl.ErrUnsupportedFieldType)
+		}
+		return parsingCompanion.IncludeField(structField), nil
+	}
+
+	if !structField.Anonymous || fieldType.Kind() != reflect.Struct || hasExplicitKey(parsingCompanion, structField, tag) {
+		return false, nil
+	}
+
+	if !structField.IsExported() {
+		// Just like encoding/json, we still promote the exported fields of
+		// unexported embedded structs. However, pointers to unexported
+		// structs can't be initialised.
+		return structField.Type.Kind() != reflect.Pointer, nil
+	}
+	return parsingCompanion.IncludeField(structField), nil
+}
+
+// hasExplicitKey checks whether the field defines its key via tags, either
+// via the tag of the source or via the tags yagcl.ParsingCompanion looks up,
+// such as the ones added via yagcl.YAGCL.AdditionalKeyTags. Inferred keys
+// and keys generated via a KeyNamingStrategy don't count.
+func hasExplicitKey(parsingCompanion yagcl.ParsingCompanion, structField reflect.StructField, tag jsonTag) bool {
+	if tag.name != "" {
+		return true
+	}
+	key := parsingCompanion.ExtractFieldKey(structField)
+	if key == "" {
+		return false
+	}
+
+	// Inferred keys are derived from the field name, while keys defined via
+	// tags don't change if the field is renamed.
+	renamed := structField
+	renamed.Name += "_"
+	return parsingCompanion.ExtractFieldKey(renamed) == key
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but returns false if a
+// nil pointer is encountered on the way.
+func fieldByIndex(structValue reflect.Value, index []int) (reflect.Value, bool) {
+	value := structValue
+	for position, fieldIndex := range index {
+		if position > 0 {
+			for value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					return reflect.Value{}, false
+				}
+				value = value.Elem()
+			}
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value, true
+}
+
+// fieldByIndexAlloc is like reflect.Value.FieldByIndex, but initialises any
+// nil pointer encountered on the way.
+func fieldByIndexAlloc(structValue reflect.Value, index []int) reflect.Value {
+	value := structValue
+	for position, fieldIndex := range index {
+		if position > 0 {
+			for value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					value.Set(reflect.New(value.Type().Elem()))
+				}
+				value = value.Elem()
+			}
+		}
+		value = value.Field(fieldIndex)
+	}
+	return value
+}
@@ -0,0 +1,201 @@
+package yagcl_json
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+type CommonConfig struct {
+	Name    string `key:"name"`
+	Verbose bool   `key:"verbose"`
+}
+
+type commonConfig struct {
+	Name string `key:"name"`
+}
+
+func Test_Parse_Embedded(t *testing.T) {
+	type configuration struct {
+		CommonConfig
+		Port int `key:"port"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service", "verbose": true, "port": 80}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.Name)
+		assert.True(t, c.Verbose)
+		assert.Equal(t, 80, c.Port)
+	}
+}
+
+func Test_Parse_Embedded_Unexported(t *testing.T) {
+	type configuration struct {
+		commonConfig
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.Name)
+	}
+}
+
+func Test_Parse_Embedded_Pointer(t *testing.T) {
+	type configuration struct {
+		*CommonConfig
+		Port int `key:"port"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service", "port": 80}`)).
+		Parse(&c)
+	if assert.NoError(t, err) && assert.NotNil(t, c.CommonConfig) {
+		assert.Equal(t, "service", c.Name)
+		assert.Equal(t, 80, c.Port)
+	}
+
+	// The embedded struct mustn't be initialised if none of its fields are
+	// set.
+	c = configuration{}
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"port": 80}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Nil(t, c.CommonConfig)
+	}
+
+	// Existing values have to be preserved.
+	c = configuration{CommonConfig: &CommonConfig{Verbose: true}}
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.Name)
+		assert.True(t, c.Verbose)
+	}
+}
+
+func Test_Parse_Embedded_ExplicitKey(t *testing.T) {
+	type configuration struct {
+		CommonConfig `key:"common"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "ignored", "common": {"name": "service"}}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.Name)
+	}
+}
+
+func Test_Parse_Embedded_AdditionalKeyTag(t *testing.T) {
+	type configuration struct {
+		CommonConfig `cfg:"common"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "ignored", "common": {"name": "service"}}`)).
+		AdditionalKeyTags("cfg").
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.Name)
+	}
+}
+
+func Test_Parse_Embedded_InferFieldKeys(t *testing.T) {
+	type configuration struct {
+		CommonConfig
+	}
+
+	// Inferred keys don't prevent embedded structs from being promoted.
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service"}`)).
+		InferFieldKeys().
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.Name)
+	}
+}
+
+func Test_Parse_Embedded_ShallowestFieldWins(t *testing.T) {
+	type configuration struct {
+		CommonConfig
+		Name string `key:"name"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.Name)
+		assert.Empty(t, c.CommonConfig.Name)
+	}
+}
+
+func Test_Parse_Embedded_SameDepth(t *testing.T) {
+	type other struct {
+		Name string `key:"name"`
+	}
+	type configuration struct {
+		CommonConfig
+		other
+	}
+
+	// Both fields are equally valid, therefore both are ignored.
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service", "verbose": true}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Empty(t, c.CommonConfig.Name)
+		assert.Empty(t, c.other.Name)
+		assert.True(t, c.Verbose)
+	}
+}
+
+func Test_Parse_Embedded_SameDepth_TaggedWins(t *testing.T) {
+	type untagged struct {
+		Name string
+	}
+	type configuration struct {
+		CommonConfig
+		untagged
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{"name": "service"}`).
+			KeyNaming(SnakeCase)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "service", c.CommonConfig.Name)
+		assert.Empty(t, c.untagged.Name)
+	}
+}
+
+func Test_Parse_Embedded_Ignored(t *testing.T) {
+	type configuration struct {
+		CommonConfig `ignore:"true"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "service"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Empty(t, c.Name)
+	}
+}
@@ -0,0 +1,231 @@
+package yagcl_json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/buger/jsonparser"
+)
+
+// GenerateSchema generates a JSON Schema (draft 2020-12) for the given
+// configuration struct. Fields are resolved by the same rules Parse applies,
+// so the schema describes exactly the keys the JSON source reads. The
+// `description` tag and the `default` tag are used to annotate the
+// properties, while `required:"true"` marks a property as required. Just
+// like the JSON source, the schema accepts null for all fields that aren't
+// required, unless NullError is used. The options should match the ones used
+// for loading, see MappingOption.
+//
+// Durations and types implementing encoding.TextUnmarshaler are described as
+// strings. Structs nested in slices and maps are decoded via encoding/json
+// and are therefore only described as objects.
+func GenerateSchema[T any](options ...MappingOption) ([]byte, error) {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	if structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't generate schema for non-struct type '%s': %w", structType, yagcl.ErrUnsupportedFieldType)
+	}
+
+	generator := &schemaGenerator{
+		mapping:  newMapping(options),
+		pointers: make(map[reflect.Type]string),
+	}
+	schema, err := generator.structSchema(structType, "#")
+	if err != nil {
+		return nil, err
+	}
+	schema.Schema = "https://json-schema.org/draft/2020-12/schema"
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(schema); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// generatedSchema is a JSON Schema produced by GenerateSchema. A struct is
+// used instead of a map, so that the keywords are written in a fixed order.
+type generatedSchema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	AnyOf                []*generatedSchema `json:"anyOf,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Default              json.RawMessage    `json:"default,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Items                *generatedSchema   `json:"items,omitempty"`
+	Properties           schemaProperties   `json:"properties,omitempty"`
+	AdditionalProperties *generatedSchema   `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// schemaProperty is a single entry of the "properties" keyword.
+type schemaProperty struct {
+	key    string
+	schema *generatedSchema
+}
+
+// schemaProperties keeps the properties in declaration order, as opposed to
+// a map, which encoding/json would sort.
+type schemaProperties []schemaProperty
+
+// MarshalJSON implements json.Marshaler.
+func (properties schemaProperties) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+	for index, property := range properties {
+		if index > 0 {
+			buffer.WriteByte(',')
+		}
+		key, err := json.Marshal(property.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(property.schema)
+		if err != nil {
+			return nil, err
+		}
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+type schemaGenerator struct {
+	*mapping
+	// pointers contains the location of all structs currently being
+	// generated, so that recursive types can be referenced via $ref.
+	pointers map[reflect.Type]string
+}
+
+func (g *schemaGenerator) structSchema(structType reflect.Type, pointer string) (*generatedSchema, error) {
+	if location, ok := g.pointers[structType]; ok {
+		return &generatedSchema{Ref: location}, nil
+	}
+	g.pointers[structType] = pointer
+	defer delete(g.pointers, structType)
+
+	fields, err := g.source.typeFields(g.companion, structType)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &generatedSchema{Type: "object", Properties: schemaProperties{}}
+	for _, field := range fields {
+		propertyPointer := pointer + "/properties/" + escapePointerToken(field.key)
+		property, err := g.fieldSchema(field, propertyPointer)
+		if err != nil {
+			return nil, err
+		}
+		schema.Properties = append(schema.Properties, schemaProperty{key: field.key, schema: property})
+		if isRequired(field.structField) {
+			schema.Required = append(schema.Required, field.key)
+		}
+	}
+	return schema, nil
+}
+
+func (g *schemaGenerator) fieldSchema(field boundField, pointer string) (*generatedSchema, error) {
+	fieldType := extractNonPointerFieldType(field.structField.Type)
+
+	var schema *generatedSchema
+	if field.tag.asString && supportsStringOption(fieldType) {
+		schema = &generatedSchema{Type: "string"}
+	} else {
+		var err error
+		if fieldType.Kind() == reflect.Struct && !hasCustomUnmarshaler(fieldType) {
+			schema, err = g.structSchema(fieldType, pointer)
+		} else {
+			schema, err = typeSchema(fieldType)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", field.structField.Name, err)
+		}
+	}
+
+	// Copy, as a referenced schema must not be modified.
+	annotated := *schema
+	annotated.Description = field.structField.Tag.Get("description")
+	if literal, ok := field.structField.Tag.Lookup("default"); ok {
+		value, dataType := parseDefaultLiteral(literal, fieldType)
+		if dataType == jsonparser.String {
+			value = append(append([]byte(`"`), value...), '"')
+		}
+		// The schema describes the value wrapped in a string.
+		if field.tag.asString && supportsStringOption(fieldType) {
+			var err error
+			if value, err = marshalJSON(string(value)); err != nil {
+				return nil, err
+			}
+		}
+		annotated.Default = value
+	}
+	if g.source.nullPolicy != NullError && !isRequired(field.structField) {
+		if annotated.Ref != "" {
+			// Keywords next to $ref are applied in addition to the
+			// referenced schema, so null has to be allowed alternatively.
+			annotated.AnyOf = []*generatedSchema{{Ref: annotated.Ref}, {Type: "null"}}
+			annotated.Ref = ""
+		} else if typeName, ok := annotated.Type.(string); ok {
+			annotated.Type = []string{typeName, "null"}
+		}
+	}
+	return &annotated, nil
+}
+
+// typeSchema describes types that are decoded via custom unmarshalers or
+// encoding/json.
+func typeSchema(valueType reflect.Type) (*generatedSchema, error) {
+	valueType = extractNonPointerFieldType(valueType)
+	pointerType := reflect.PointerTo(valueType)
+	if pointerType.Implements(jsonUnmarshalerType) {
+		// Could be anything.
+		return &generatedSchema{}, nil
+	}
+	if pointerType.Implements(textUnmarshalerType) || valueType == reflect.TypeOf(time.Duration(0)) {
+		return &generatedSchema{Type: "string"}, nil
+	}
+
+	switch valueType.Kind() {
+	case reflect.String:
+		return &generatedSchema{Type: "string"}, nil
+	case reflect.Bool:
+		return &generatedSchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &generatedSchema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		minimum := 0
+		return &generatedSchema{Type: "integer", Minimum: &minimum}, nil
+	case reflect.Float32, reflect.Float64:
+		return &generatedSchema{Type: "number"}, nil
+	case reflect.Struct:
+		return &generatedSchema{Type: "object"}, nil
+	case reflect.Interface:
+		return &generatedSchema{}, nil
+	case reflect.Slice, reflect.Array:
+		// encoding/json expects base64 strings for byte slices.
+		if valueType.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.Uint8 {
+			return &generatedSchema{Type: "string"}, nil
+		}
+		items, err := typeSchema(valueType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &generatedSchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := typeSchema(valueType.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &generatedSchema{Type: "object", AdditionalProperties: values}, nil
+	}
+	return nil, fmt.Errorf("type '%s' can't be described: %w", valueType, yagcl.ErrUnsupportedFieldType)
+}
@@ -0,0 +1,214 @@
+package yagcl_json
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GenerateSchema(t *testing.T) {
+	type database struct {
+		Host string `key:"host" required:"true"`
+		Port uint16 `key:"port" default:"5432"`
+	}
+	type configuration struct {
+		CommonConfig
+		Level    string                   `json:"level" description:"Minimum log level." default:"info"`
+		Timeout  time.Duration            `key:"timeout" default:"30s"`
+		Custom   *customTextUnmarshalable `key:"custom"`
+		Any      customJSONUnmarshalable  `key:"any"`
+		Ratio    *float64                 `key:"ratio"`
+		Count    int                      `json:"count,string"`
+		Retries  int                      `json:"retries,string" default:"3"`
+		Mode     string                   `json:"mode,string" default:"fast"`
+		Tags     []string                 `key:"tags"`
+		Limits   map[string]int           `key:"limits"`
+		Database *database                `key:"database"`
+		Ignored  string                   `key:"ignored" ignore:"true"`
+		Skipped  string                   `json:"-"`
+		internal string
+	}
+
+	schema, err := GenerateSchema[configuration]()
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"name": {"type": ["string", "null"]},
+				"verbose": {"type": ["boolean", "null"]},
+				"level": {"type": ["string", "null"], "description": "Minimum log level.", "default": "info"},
+				"timeout": {"type": ["string", "null"], "default": "30s"},
+				"custom": {"type": ["string", "null"]},
+				"any": {},
+				"ratio": {"type": ["number", "null"]},
+				"count": {"type": ["string", "null"]},
+				"retries": {"type": ["string", "null"], "default": "3"},
+				"mode": {"type": ["string", "null"], "default": "\"fast\""},
+				"tags": {"type": ["array", "null"], "items": {"type": "string"}},
+				"limits": {"type": ["object", "null"], "additionalProperties": {"type": "integer"}},
+				"database": {
+					"type": ["object", "null"],
+					"properties": {
+						"host": {"type": "string"},
+						"port": {"type": ["integer", "null"], "minimum": 0, "default": 5432}
+					},
+					"required": ["host"]
+				}
+			}
+		}`, string(schema))
+	}
+}
+
+func Test_GenerateSchema_Recursive(t *testing.T) {
+	type node struct {
+		Name     string `key:"name"`
+		Children []node `key:"children"`
+		Next     *node  `key:"next"`
+	}
+	type configuration struct {
+		Root node `key:"root"`
+	}
+
+	schema, err := GenerateSchema[configuration]()
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"root": {
+					"type": ["object", "null"],
+					"properties": {
+						"name": {"type": ["string", "null"]},
+						"children": {"type": ["array", "null"], "items": {"type": "object"}},
+						"next": {"anyOf": [{"$ref": "#/properties/root"}, {"type": "null"}]}
+					}
+				}
+			}
+		}`, string(schema))
+
+		var c configuration
+		err := yagcl.New[configuration]().
+			Add(Source().String(`{"root": {"name": "a", "next": {"name": 1}}}`).Schema(schema)).
+			Parse(&c)
+		assert.ErrorIs(t, err, ErrSchemaViolation)
+	}
+}
+
+func Test_GenerateSchema_RecursivePointer(t *testing.T) {
+	type configuration struct {
+		Name string         `key:"name"`
+		Self *configuration `key:"self"`
+	}
+
+	schema, err := GenerateSchema[configuration]()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	data, err := Marshal(configuration{Name: "a", Self: &configuration{Name: "b"}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	var c configuration
+	err = yagcl.New[configuration]().
+		Add(Source().Bytes(data).Schema(schema)).
+		Parse(&c)
+	if assert.NoError(t, err) && assert.NotNil(t, c.Self) {
+		assert.Equal(t, "b", c.Self.Name)
+		assert.Nil(t, c.Self.Self)
+	}
+
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"self": {"self": 1}}`).Schema(schema)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrSchemaViolation)
+}
+
+func Test_GenerateSchema_MatchesSource(t *testing.T) {
+	type configuration struct {
+		Port    int           `key:"port" required:"true"`
+		Timeout time.Duration `key:"timeout"`
+	}
+
+	schema, err := GenerateSchema[configuration]()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var c configuration
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"port": 8080, "timeout": "5s"}`).Schema(schema)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 8080, c.Port)
+		assert.Equal(t, 5*time.Second, c.Timeout)
+	}
+
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"timeout": 5}`).Schema(schema)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrSchemaViolation)
+}
+
+func Test_GenerateSchema_Unsupported(t *testing.T) {
+	type configuration struct {
+		Complex complex128 `key:"complex"`
+	}
+	_, err := GenerateSchema[configuration]()
+	assert.ErrorIs(t, err, yagcl.ErrUnsupportedFieldType)
+
+	_, err = GenerateSchema[string]()
+	assert.ErrorIs(t, err, yagcl.ErrUnsupportedFieldType)
+}
+
+func Test_GenerateSchema_MissingKey(t *testing.T) {
+	type configuration struct {
+		Field string
+	}
+	_, err := GenerateSchema[configuration]()
+	assert.ErrorIs(t, err, yagcl.ErrExportedFieldMissingKey)
+}
+
+func Test_GenerateSchema_Options(t *testing.T) {
+	type configuration struct {
+		ServerPort int `key:"port" required:"true"`
+		LogLevel   string
+	}
+
+	source := Source().String(`{"port": 1, "log_level": null}`).KeyNaming(SnakeCase)
+	schema, err := GenerateSchema[configuration](WithSource(source))
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"port": {"type": "integer"},
+				"log_level": {"type": ["string", "null"]}
+			},
+			"required": ["port"]
+		}`, string(schema))
+
+		// The schema accepts everything the source accepts.
+		var c configuration
+		err := yagcl.New[configuration]().Add(source.Schema(schema)).Parse(&c)
+		assert.NoError(t, err)
+	}
+
+	schema, err = GenerateSchema[configuration](
+		WithSource(Source().String(`{}`).Null(NullError)),
+		WithLoader(yagcl.New[configuration]().InferFieldKeys()))
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"$schema": "https://json-schema.org/draft/2020-12/schema",
+			"type": "object",
+			"properties": {
+				"port": {"type": "integer"},
+				"loglevel": {"type": "string"}
+			},
+			"required": ["port"]
+		}`, string(schema))
+	}
+}
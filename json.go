@@ -1,6 +1,7 @@
 package yagcl_json
 
 import (
+	"context"
 	"encoding"
 	"encoding/json"
 	"errors"
@@ -24,11 +25,59 @@ var ErrNoDataSourceSpecified = errors.New("no data source specified; call Bytes(
 // or Reader of the JSONSourceSetupStepOne interface have been called.
 var ErrMultipleDataSourcesSpecified = errors.New("more than one data source specified; only call one of Bytes(), String(), Reader() or Path()")
 
+// ErrNullValue is returned if a field is set to null, but NullError is used.
+var ErrNullValue = errors.New("null isn't allowed as a value")
+
+// NullPolicy defines how JSON null values are treated. The policy is applied
+// to all fields, no matter their type. Note that null values inside of
+// arrays and maps are decoded like encoding/json does.
+type NullPolicy int
+
+const (
+	// NullKeep ignores null values, just like missing keys. Therefore
+	// previously set values, for example by sources with a lower priority,
+	// are kept. This is the default.
+	NullKeep NullPolicy = iota
+	// NullReset sets the field to its zero value, which is nil for pointers,
+	// slices and maps.
+	NullReset
+	// NullError causes parsing to fail with ErrNullValue.
+	NullError
+)
+
 type jsonSourceImpl struct {
-	must   bool
-	path   string
-	bytes  []byte
-	reader io.Reader
+	must             bool
+	name             string
+	keyMatching      KeyMatchingPolicy
+	keyNaming        KeyNamingStrategy
+	nullPolicy       NullPolicy
+	presence         *Presence
+	provenance       *Provenance
+	required         []string
+	schema           []byte
+	ctx              context.Context
+	limits           limits
+	duplicateKeys    DuplicateKeyPolicy
+	private          bool
+	zstd             DecompressFunc
+	decrypter        Decrypter
+	requireEncrypted bool
+	path             string
+	bytes            []byte
+	reader           io.Reader
+
+	// document is the data currently being parsed.
+	document []byte
+	// encrypted contains the offsets of all values in the document that have
+	// been decrypted from "ENC[...]" strings.
+	encrypted map[int]bool
+	// missingRequired collects all required fields not found in the
+	// document while parsing.
+	missingRequired *RequiredError
+	// loaded contains the JSON paths of all values set by the latest parse.
+	// It's kept after parsing, so that sources parsed later during the same
+	// load can look it up, see setByPreviousSource.
+	loaded map[string]bool
 }
 
 // JSONSourceSetupStepOne enforces the API caller to specify any data source to
@@ -55,6 +104,77 @@ type JSONSourceOptionalSetup[T yagcl.Source] interface {
 	// FIXME Clarify when this case happens. Only when not finding a file?
 	// FIXME does must actually make sense for anything but files?
 	Must() T
+	// KeyMatching defines how JSON keys are compared to the keys defined by
+	// the struct fields. By default, keys have to match exactly.
+	KeyMatching(KeyMatchingPolicy) T
+	// KeyNaming defines a strategy for deriving JSON keys from the Go field
+	// names. It is only applied to fields that define no key via tags.
+	KeyNaming(KeyNamingStrategy) T
+	// Null defines how JSON null values are treated. By default, null
+	// values are ignored, see NullKeep.
+	Null(NullPolicy) T
+	// TrackPresence records which fields have been set during parsing.
+	// The passed Presence is reset whenever the source is parsed.
+	TrackPresence(*Presence) T
+	// Name defines a human readable name for the source, which is used for
+	// example in a Provenance. By default, the path is used for Path
+	// sources.
+	Name(string) T
+	// TrackProvenance records the origin of every value set by the source.
+	// A Provenance may be shared between multiple sources, as long as their
+	// names differ, since the origins recorded under the name of the source
+	// are replaced whenever the source is parsed.
+	TrackProvenance(*Provenance) T
+	// Required defines JSON paths, such as "database.host", that have to be
+	// present in the document and mustn't be null. This is an alternative to
+	// the `required:"true"` tag, which also works for paths that aren't
+	// bound to any field.
+	Required(paths ...string) T
+	// Schema defines a JSON Schema the document is validated against before
+	// any field is parsed. Only a subset of draft 2020-12 is supported, see
+	// SchemaError for the reported violations.
+	Schema([]byte) T
+	// Context defines a context that is honored while Parse reads the data
+	// source. Once the context is done, parsing fails with the context's
+	// error. This allows aborting blocking reads from files and readers
+	// implementing io.Closer.
+	Context(context.Context) T
+	// MaxBytes limits the size of the document. Reading stops as soon as
+	// the limit has been exceeded, returning a LimitError.
+	MaxBytes(int64) T
+	// MaxDepth limits how deeply objects and arrays may be nested.
+	MaxDepth(int) T
+	// MaxArrayLen limits the number of elements of each array.
+	MaxArrayLen(int) T
+	// MaxStringLen limits the length of each string, including keys, in
+	// bytes.
+	MaxStringLen(int) T
+	// DuplicateKeys defines how objects containing the same key more than
+	// once are treated. By default, the first occurrence is used.
+	DuplicateKeys(DuplicateKeyPolicy) T
+	// RequirePrivate refuses to load files that aren't owned by the current
+	// user or that grant any permissions to the group or others, returning
+	// ErrNotPrivate. This only affects Path sources. On platforms other than
+	// Unix, loading always fails, as the check can't be performed.
+	RequirePrivate() T
+	// Zstd enables reading zstd compressed data from Path and Reader
+	// sources. gzip and bzip2 are always supported. Compressed data is
+	// detected by its magic bytes or the file extension.
+	Zstd(DecompressFunc) T
+	// Decrypt decrypts the document before parsing, unless it already is a
+	// JSON object. Additionally, all string values of the form
+	// "ENC[<base64 ciphertext>]" are decrypted, which keeps the rest of the
+	// document readable. See AESGCM for a built-in Decrypter.
+	Decrypt(Decrypter) T
+	// RequireEncrypted refuses to load documents that aren't encrypted as a
+	// whole, returning ErrNotEncrypted. This only has an effect if a
+	// Decrypter has been defined via Decrypt.
+	RequireEncrypted() T
+	// ParseContext is like Parse, but uses the given context instead of the
+	// one defined via Context. Reading is aborted once the context is done.
+	// Readers implementing io.Closer are closed for that purpose, while
+	// all other readers are only checked between reads.
+	ParseContext(context.Context, yagcl.ParsingCompanion, any) (bool, error)
 }
 
 // Source creates a source for a JSON file.
@@ -68,6 +188,129 @@ func (s *jsonSourceImpl) Must() *jsonSourceImpl {
 	return s
 }
 
+// KeyMatching implements JSONSourceOptionalSetup.KeyMatching.
+func (s *jsonSourceImpl) KeyMatching(policy KeyMatchingPolicy) *jsonSourceImpl {
+	s.keyMatching = policy
+	return s
+}
+
+// KeyNaming implements JSONSourceOptionalSetup.KeyNaming.
+func (s *jsonSourceImpl) KeyNaming(strategy KeyNamingStrategy) *jsonSourceImpl {
+	s.keyNaming = strategy
+	return s
+}
+
+// Null implements JSONSourceOptionalSetup.Null.
+func (s *jsonSourceImpl) Null(policy NullPolicy) *jsonSourceImpl {
+	s.nullPolicy = policy
+	return s
+}
+
+// TrackPresence implements JSONSourceOptionalSetup.TrackPresence.
+func (s *jsonSourceImpl) TrackPresence(presence *Presence) *jsonSourceImpl {
+	s.presence = presence
+	return s
+}
+
+// Name implements JSONSourceOptionalSetup.Name.
+func (s *jsonSourceImpl) Name(name string) *jsonSourceImpl {
+	s.name = name
+	return s
+}
+
+// TrackProvenance implements JSONSourceOptionalSetup.TrackProvenance.
+func (s *jsonSourceImpl) TrackProvenance(provenance *Provenance) *jsonSourceImpl {
+	s.provenance = provenance
+	return s
+}
+
+// sourceName returns the name defined via Name or a fallback based on the
+// type of data source.
+func (s *jsonSourceImpl) sourceName() string {
+	if s.name != "" {
+		return s.name
+	}
+	if s.path != "" {
+		return s.path
+	}
+	if s.reader != nil {
+		return "<reader>"
+	}
+	return "<bytes>"
+}
+
+// Required implements JSONSourceOptionalSetup.Required.
+func (s *jsonSourceImpl) Required(paths ...string) *jsonSourceImpl {
+	s.required = append(s.required, paths...)
+	return s
+}
+
+// Schema implements JSONSourceOptionalSetup.Schema.
+func (s *jsonSourceImpl) Schema(schema []byte) *jsonSourceImpl {
+	s.schema = schema
+	return s
+}
+
+// Context implements JSONSourceOptionalSetup.Context.
+func (s *jsonSourceImpl) Context(ctx context.Context) *jsonSourceImpl {
+	s.ctx = ctx
+	return s
+}
+
+// MaxBytes implements JSONSourceOptionalSetup.MaxBytes.
+func (s *jsonSourceImpl) MaxBytes(maxBytes int64) *jsonSourceImpl {
+	s.limits.maxBytes = maxBytes
+	return s
+}
+
+// MaxDepth implements JSONSourceOptionalSetup.MaxDepth.
+func (s *jsonSourceImpl) MaxDepth(maxDepth int) *jsonSourceImpl {
+	s.limits.maxDepth = maxDepth
+	return s
+}
+
+// MaxArrayLen implements JSONSourceOptionalSetup.MaxArrayLen.
+func (s *jsonSourceImpl) MaxArrayLen(maxArrayLen int) *jsonSourceImpl {
+	s.limits.maxArrayLen = maxArrayLen
+	return s
+}
+
+// MaxStringLen implements JSONSourceOptionalSetup.MaxStringLen.
+func (s *jsonSourceImpl) MaxStringLen(maxStringLen int) *jsonSourceImpl {
+	s.limits.maxStringLen = maxStringLen
+	return s
+}
+
+// DuplicateKeys implements JSONSourceOptionalSetup.DuplicateKeys.
+func (s *jsonSourceImpl) DuplicateKeys(policy DuplicateKeyPolicy) *jsonSourceImpl {
+	s.duplicateKeys = policy
+	return s
+}
+
+// RequirePrivate implements JSONSourceOptionalSetup.RequirePrivate.
+func (s *jsonSourceImpl) RequirePrivate() *jsonSourceImpl {
+	s.private = true
+	return s
+}
+
+// Zstd implements JSONSourceOptionalSetup.Zstd.
+func (s *jsonSourceImpl) Zstd(decompress DecompressFunc) *jsonSourceImpl {
+	s.zstd = decompress
+	return s
+}
+
+// Decrypt implements JSONSourceOptionalSetup.Decrypt.
+func (s *jsonSourceImpl) Decrypt(decrypter Decrypter) *jsonSourceImpl {
+	s.decrypter = decrypter
+	return s
+}
+
+// RequireEncrypted implements JSONSourceOptionalSetup.RequireEncrypted.
+func (s *jsonSourceImpl) RequireEncrypted() *jsonSourceImpl {
+	s.requireEncrypted = true
+	return s
+}
+
 // KeyTag implements Source.Key.
 func (s *jsonSourceImpl) KeyTag() string {
 	return "json"
@@ -100,7 +343,7 @@ func (s *jsonSourceImpl) Reader(reader io.Reader) JSONSourceOptionalSetup[*jsonS
 // getBytes attempts to retrieve data via one of the defined data sources.
 // A call to jsonSourceImpl.verify should've been done before calling this in
 // order to avoid undefined behaviour.
-func (s *jsonSourceImpl) getBytes() (data []byte, err error) {
+func (s *jsonSourceImpl) getBytes(ctx context.Context) (data []byte, err error) {
 	// Do bytes first, since it saves us the error handling code.
 	if len(s.bytes) > 0 {
 		data = s.bytes
@@ -118,7 +361,18 @@ func (s *jsonSourceImpl) getBytes() (data []byte, err error) {
 	}()
 
 	if s.path != "" {
-		data, err = os.ReadFile(s.path)
+		var file *os.File
+		file, err = os.Open(s.path)
+		if err != nil {
+			return
+		}
+		defer file.Close()
+		if s.private {
+			if err = checkPrivate(s.path, file); err != nil {
+				return
+			}
+		}
+		data, err = s.readAll(ctx, file)
 		return
 	}
 
@@ -126,7 +380,7 @@ func (s *jsonSourceImpl) getBytes() (data []byte, err error) {
 		if closer, ok := s.reader.(io.Closer); ok {
 			defer closer.Close()
 		}
-		data, err = io.ReadAll(s.reader)
+		data, err = s.readAll(ctx, s.reader)
 		return
 	}
 
@@ -135,6 +389,73 @@ func (s *jsonSourceImpl) getBytes() (data []byte, err error) {
 	return
 }
 
+// readAll reads until EOF, unless the context is done first. In that case,
+// the reader is closed, as that usually unblocks pending reads. Readers that
+// can't be closed are read on the calling goroutine instead, checking the
+// context between reads, as a pending read couldn't be unblocked and a
+// goroutine reading in the background would leak.
+func (s *jsonSourceImpl) readAll(ctx context.Context, reader io.Reader) ([]byte, error) {
+	closer, isCloser := reader.(io.Closer)
+	if ctx.Done() == nil {
+		return s.readDecompressed(reader)
+	}
+	if !isCloser {
+		data, err := s.readDecompressed(&contextReader{ctx: ctx, reader: reader})
+		if ctx.Err() != nil {
+			return nil, s.contextError(ctx)
+		}
+		return data, err
+	}
+
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := s.readDecompressed(reader)
+		done <- result{data: data, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.data, result.err
+	case <-ctx.Done():
+		closer.Close()
+		return nil, s.contextError(ctx)
+	}
+}
+
+// contextReader fails as soon as the context is done, but doesn't interrupt
+// pending reads.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(buffer []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(buffer)
+}
+
+// readDecompressed reads the decompressed data. Limits apply to the
+// decompressed data, as that's what ends up in memory.
+func (s *jsonSourceImpl) readDecompressed(reader io.Reader) ([]byte, error) {
+	decompressed, err := s.decompress(reader)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDecompressor(decompressed)
+	return io.ReadAll(s.limitReader(decompressed))
+}
+
+// contextError wraps the error of a done context with the source name.
+func (s *jsonSourceImpl) contextError(ctx context.Context) error {
+	return fmt.Errorf("parsing source '%s' has been aborted: %w", s.sourceName(), ctx.Err())
+}
+
 // verify checks whether the source has been configured correctly. We attempt
 // avoiding any condiguration errors by API design.
 func (s *jsonSourceImpl) verify() error {
@@ -161,11 +482,30 @@ func (s *jsonSourceImpl) verify() error {
 
 // Parse implements Source.Parse.
 func (s *jsonSourceImpl) Parse(parsingCompanion yagcl.ParsingCompanion, configurationStruct any) (bool, error) {
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return s.ParseContext(ctx, parsingCompanion, configurationStruct)
+}
+
+// ParseContext implements JSONSourceOptionalSetup.ParseContext.
+func (s *jsonSourceImpl) ParseContext(ctx context.Context, parsingCompanion yagcl.ParsingCompanion, configurationStruct any) (bool, error) {
 	if err := s.verify(); err != nil {
 		return false, err
 	}
 
-	bytes, err := s.getBytes()
+	// Values of previous loads mustn't influence this one, even if the
+	// source doesn't load anything.
+	s.loaded = nil
+	if s.provenance != nil {
+		s.provenance.forget(s.sourceName())
+	}
+	if ctx.Err() != nil {
+		return false, s.contextError(ctx)
+	}
+
+	bytes, err := s.getBytes(ctx)
 	if err != nil {
 		if !s.must && err == yagcl.ErrSourceNotFound {
 			return false, nil
@@ -173,191 +513,480 @@ func (s *jsonSourceImpl) Parse(parsingCompanion yagcl.ParsingCompanion, configur
 		return false, err
 	}
 
-	_, err = s.parse(parsingCompanion, bytes, nil, reflect.Indirect(reflect.ValueOf(configurationStruct)))
-	return err == nil, err
+	var encrypted map[int]bool
+	if s.decrypter != nil {
+		if bytes, encrypted, err = s.decrypt(ctx, bytes); err != nil {
+			return false, err
+		}
+	}
+
+	// jsonparser doesn't know about comments, it merely skips them by
+	// accident in some cases. Since we iterate over objects, we have to
+	// get rid of them beforehand.
+	bytes = blankComments(bytes)
+	if err := s.checkLimits(bytes); err != nil {
+		return false, err
+	}
+	if bytes, err = s.resolveDuplicateKeys(bytes); err != nil {
+		return false, err
+	}
+
+	if s.presence != nil {
+		s.presence.reset()
+	}
+	if s.schema != nil {
+		if err := validateSchema(s.schema, bytes); err != nil {
+			return false, err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return false, s.contextError(ctx)
+	}
+
+	s.document = bytes
+	s.encrypted = encrypted
+	s.missingRequired = &RequiredError{}
+	defer func() {
+		s.document = nil
+		s.encrypted = nil
+		s.missingRequired = nil
+	}()
+
+	_, err = s.parse(parsingCompanion, bytes, 0, nil, reflect.Indirect(reflect.ValueOf(configurationStruct)))
+	if err != nil {
+		return false, err
+	}
+
+	if err := s.checkRequiredPaths(bytes); err != nil {
+		return false, err
+	}
+	if len(s.missingRequired.Missing) > 0 || len(s.missingRequired.Null) > 0 {
+		return false, s.missingRequired
+	}
+	return true, nil
 }
 
-func (s *jsonSourceImpl) parse(parsingCompanion yagcl.ParsingCompanion, bytes []byte, parentJsonPath []string, structValue reflect.Value) (bool, error) {
+// parse sets all fields of structValue that can be found in objectBytes.
+// objectBytes is expected to contain a single JSON object, which starts at
+// objectOffset in the document.
+func (s *jsonSourceImpl) parse(parsingCompanion yagcl.ParsingCompanion, objectBytes []byte, objectOffset int, parentJsonPath []string, structValue reflect.Value) (bool, error) {
 	var hasAnyFieldBeenSet bool
-	structType := structValue.Type()
-	for i := 0; i < structValue.NumField(); i++ {
-		structField := structType.Field(i)
-		// By default, all exported fiels are not ignored and all exported
-		// fields are. Unexported fields can't be un-ignored though.
-		if !parsingCompanion.IncludeField(structField) {
+	fields, err := s.typeFields(parsingCompanion, structValue.Type())
+	if err != nil {
+		return false, err
+	}
+
+	for _, field := range fields {
+		jsonPath := append(parentJsonPath[:len(parentJsonPath):len(parentJsonPath)], field.key)
+
+		member, err := s.lookupKey(objectBytes, field.key)
+		if errors.Is(err, ErrAmbiguousKey) {
+			return hasAnyFieldBeenSet, fmt.Errorf("error accessing json field '%s': %w", jsonPath, err)
+		}
+		if err != nil {
+			return hasAnyFieldBeenSet, newJsonparserError(jsonPath, err)
+		}
+		// Since not every field in the struct might be in the JSON, we
+		// ignore these "errors".
+		if member == nil {
+			s.recordPresence(jsonPath, false)
+			if isRequired(field.structField) {
+				s.missingRequired.addMissing(formatPath(jsonPath))
+			}
+			hasDefaultBeenSet, err := s.applyDefault(parsingCompanion, field, jsonPath, structValue)
+			hasAnyFieldBeenSet = hasAnyFieldBeenSet || hasDefaultBeenSet
+			if err != nil {
+				return hasAnyFieldBeenSet, err
+			}
 			continue
 		}
 
-		jsonKey, err := s.extractJSONKey(parsingCompanion, structField)
+		// Values that don't originate from the document, such as defaults,
+		// don't have an offset.
+		valueOffset := -1
+		if objectOffset >= 0 {
+			valueOffset = objectOffset + member.offset
+		}
+
+		if member.dataType == jsonparser.Null {
+			if isRequired(field.structField) {
+				s.missingRequired.addNull(formatPath(jsonPath))
+			}
+			switch s.nullPolicy {
+			case NullError:
+				return hasAnyFieldBeenSet, fmt.Errorf("field '%s' is null: %w", jsonPath, ErrNullValue)
+			case NullReset:
+				hasAnyFieldBeenSet = true
+				// Unreachable fields are zero already.
+				if fieldValue, reachable := fieldByIndex(structValue, field.index); reachable {
+					fieldValue.Set(reflect.Zero(field.structField.Type))
+				}
+				s.recordProvenance(jsonPath, valueOffset)
+			}
+			s.recordPresence(jsonPath, s.nullPolicy == NullReset)
+			continue
+		}
+
+		hasFieldBeenSet, err := s.setField(parsingCompanion, field, jsonPath, structValue, member.value, member.dataType, valueOffset)
+		hasAnyFieldBeenSet = hasAnyFieldBeenSet || hasFieldBeenSet
 		if err != nil {
 			return hasAnyFieldBeenSet, err
 		}
-		jsonPath := append(parentJsonPath, jsonKey)
+		s.recordPresence(jsonPath, hasFieldBeenSet)
+	}
 
-		valueBytes, dataType, _, err := jsonparser.Get(bytes, jsonPath...)
-		// Since not every field in the struct might be in the JSON, we
-		// ignore these "errors".
-		if err == jsonparser.KeyPathNotFoundError {
-			continue
+	return hasAnyFieldBeenSet, nil
+}
+
+// setField decodes the given value and assigns it to the field of
+// structValue. false is returned if the value is a JSON object, but none of
+// the fields of the corresponding struct have been set.
+func (s *jsonSourceImpl) setField(
+	parsingCompanion yagcl.ParsingCompanion,
+	field boundField,
+	jsonPath []string,
+	structValue reflect.Value,
+	valueBytes []byte,
+	dataType jsonparser.ValueType,
+	valueOffset int,
+) (bool, error) {
+	structField, tag := field.structField, field.tag
+	fieldType := extractNonPointerFieldType(structField.Type)
+	// Promoted fields of embedded struct pointers might not be
+	// reachable yet. We don't want to initialise the embedded struct
+	// unless a value is actually set, so we use a temporary value.
+	fieldValue, reachable := fieldByIndex(structValue, field.index)
+	if !reachable {
+		fieldValue = reflect.New(structField.Type).Elem()
+	}
+
+	// Just like encoding/json, we expect the actual value to be
+	// wrapped in a JSON string.
+	if tag.asString && supportsStringOption(fieldType) {
+		if dataType != jsonparser.String {
+			return false, fmt.Errorf("field '%s' had an incorrect JSON type (%s != string), as it has the option 'string': %w", structField.Name, dataType.String(), yagcl.ErrParseValue)
 		}
+		unquoted, err := jsonparser.ParseString(valueBytes)
 		if err != nil {
-			return hasAnyFieldBeenSet, newJsonparserError(jsonPath, err)
+			return false, newJsonparserError(jsonPath, err)
+		}
+		var end int
+		valueBytes, dataType, end, err = jsonparser.Get([]byte(unquoted))
+		if err != nil {
+			return false, newJsonparserError(jsonPath, err)
 		}
+		// jsonparser stops after the first value, ignoring anything else.
+		if len(strings.TrimSpace(unquoted[end:])) > 0 {
+			return false, fmt.Errorf("field '%s' contained more than a single value in its string: %w", structField.Name, yagcl.ErrParseValue)
+		}
+		// Only strings may be quoted twice.
+		if dataType == jsonparser.String && fieldType.Kind() != reflect.String {
+			return false, fmt.Errorf("field '%s' contained a quoted string instead of a %s: %w", structField.Name, fieldType.Kind(), yagcl.ErrParseValue)
+		}
+	}
 
-		fieldType := extractNonPointerFieldType(structField.Type)
-		fieldValue := structValue.Field(i)
-		var value any
-
-		// In this section we check whether custom unmarshallers are present.
-		// Types with a custom unmarshaller have to be checked first before
-		// attempting to parse them using default behaviour, as the behaviour
-		// might differ from std/json otherwise.
-
-		// Technically this check isn't required, as we already filter out
-		// unexported fields. However, I am unsure whether this behaviour is set
-		// in stone, as it hasn't been documented properly.
-		// https://stackoverflow.com/questions/50279840/when-is-go-reflect-caninterface-false
-		var customUnmarshalApplied bool
-		if fieldValue.CanInterface() {
-			newType := extractNonPointerFieldType(fieldValue.Type())
-			// New pointer value, since non-pointers can't implement json.Unmarshaler.
-			parsed := reflect.New(newType)
-			if u, ok := parsed.Interface().(json.Unmarshaler); ok {
-				// Since jsonparser strips the quotes from strings, we need to add
-				// them back in order for custom unmarshalling not to fail.
-				if dataType == jsonparser.String {
-					// This means that strings might still contain escape sequences.
-					// The implementation of UnmarshalJSON has to treat this.
-					// FIXME See if this behaviour is the same in standard go json.
-					valueBytes = append(append([]byte(`"`), valueBytes...), byte('"'))
-				}
+	var value any
 
-				if err := u.UnmarshalJSON(valueBytes); err != nil {
-					return hasAnyFieldBeenSet, newUnmarshalError(jsonPath, err)
+	// In this section we check whether custom unmarshallers are present.
+	// Types with a custom unmarshaller have to be checked first before
+	// attempting to parse them using default behaviour, as the behaviour
+	// might differ from std/json otherwise.
+
+	// Technically this check isn't required, as we already filter out
+	// unexported fields. However, I am unsure whether this behaviour is set
+	// in stone, as it hasn't been documented properly.
+	// https://stackoverflow.com/questions/50279840/when-is-go-reflect-caninterface-false
+	var customUnmarshalApplied bool
+	if fieldValue.CanInterface() {
+		newType := extractNonPointerFieldType(fieldValue.Type())
+		// New pointer value, since non-pointers can't implement json.Unmarshaler.
+		parsed := reflect.New(newType)
+		if u, ok := parsed.Interface().(json.Unmarshaler); ok {
+			// Since jsonparser strips the quotes from strings, we need to add
+			// them back in order for custom unmarshalling not to fail.
+			if dataType == jsonparser.String {
+				// This means that strings might still contain escape sequences.
+				// The implementation of UnmarshalJSON has to treat this.
+				// FIXME See if this behaviour is the same in standard go json.
+				valueBytes = append(append([]byte(`"`), valueBytes...), byte('"'))
+			}
+
+			if err := u.UnmarshalJSON(valueBytes); err != nil {
+				return false, newUnmarshalError(jsonPath, err)
+			}
+
+			value = u
+			customUnmarshalApplied = true
+		} else if u, ok := parsed.Interface().(encoding.TextUnmarshaler); ok {
+			// Only supported for string, as it is "TextUnmarshaler".
+			if dataType == jsonparser.String {
+				if err := u.UnmarshalText(valueBytes); err != nil {
+					return false, newUnmarshalError(jsonPath, err)
 				}
 
 				value = u
 				customUnmarshalApplied = true
-			} else if u, ok := parsed.Interface().(encoding.TextUnmarshaler); ok {
-				// Only supported for string, as it is "TextUnmarshaler".
-				if dataType == jsonparser.String {
-					if err := u.UnmarshalText(valueBytes); err != nil {
-						return hasAnyFieldBeenSet, newUnmarshalError(jsonPath, err)
-					}
-
-					value = u
-					customUnmarshalApplied = true
-				}
 			}
 		}
+	}
 
-		if !customUnmarshalApplied {
-			switch fieldType.Kind() {
-			case reflect.String:
-				if dataType != jsonparser.String {
-					return hasAnyFieldBeenSet, fmt.Errorf("field '%s' had an incorrect JSON type (%s != string): %w", structField.Name, dataType.String(), yagcl.ErrParseValue)
-				}
-				// Can't use the raw value, as there might be escape sequences.
-				// This is basically what jsonparser.GetString does.
-				value, err = jsonparser.ParseString(valueBytes)
-				if err != nil {
-					return hasAnyFieldBeenSet, newJsonparserError(jsonPath, err)
-				}
-			case reflect.Struct:
-				// We can't operate on any zero value, therefore we create a
-				// temporary value for the struct.
-				var structValue reflect.Value
-				if fieldValue.IsZero() {
-					structValue = reflect.New(fieldType)
-				} else {
-					structValue = fieldValue
-				}
-				structValue = reflect.Indirect(structValue)
+	if !customUnmarshalApplied {
+		switch fieldType.Kind() {
+		case reflect.String:
+			if dataType != jsonparser.String {
+				return false, fmt.Errorf("field '%s' had an incorrect JSON type (%s != string): %w", structField.Name, dataType.String(), yagcl.ErrParseValue)
+			}
+			// Can't use the raw value, as there might be escape sequences.
+			// This is basically what jsonparser.GetString does.
+			var err error
+			value, err = jsonparser.ParseString(valueBytes)
+			if err != nil {
+				return false, newJsonparserError(jsonPath, err)
+			}
+		case reflect.Struct:
+			if dataType != jsonparser.Object {
+				return false, fmt.Errorf("field '%s' had an incorrect JSON type (%s != object): %w", structField.Name, dataType.String(), yagcl.ErrParseValue)
+			}
 
-				hasAnySubStructFieldBeenSet, err := s.parse(parsingCompanion, bytes, jsonPath, structValue)
-				hasAnyFieldBeenSet = hasAnyFieldBeenSet || hasAnySubStructFieldBeenSet
-				if err != nil {
-					return hasAnyFieldBeenSet, err
-				}
+			// We can't operate on any zero value, therefore we create a
+			// temporary value for the struct.
+			structValue := newStructTarget(fieldValue, fieldType)
+			hasAnySubStructFieldBeenSet, err := s.parse(parsingCompanion, valueBytes, valueOffset, jsonPath, structValue)
+			if err != nil {
+				return hasAnySubStructFieldBeenSet, err
+			}
 
-				// Only if any field of our temporary struct has been set, we
-				// actually use the initialised struct for its parent.
-				// Otherwise we'd initialise struct pointers that don't have a
-				// single field set, losing the information of what values have
-				// actually been set. Additionally, executing the rest of the
-				// loop would cause a panic, as we'd try to access the value
-				// that hasn't been initiliased.
-				if !hasAnySubStructFieldBeenSet {
-					continue
-				}
+			// Only if any field of our temporary struct has been set, we
+			// actually use the initialised struct for its parent.
+			// Otherwise we'd initialise struct pointers that don't have a
+			// single field set, losing the information of what values have
+			// actually been set. Additionally, executing the rest of the
+			// loop would cause a panic, as we'd try to access the value
+			// that hasn't been initiliased.
+			if !hasAnySubStructFieldBeenSet {
+				return false, nil
+			}
 
-				value = structValue.Interface()
-			case reflect.Complex64, reflect.Complex128:
-				{
-					// Complex isn't supported, as for example it also isn't supported
-					// by the stdlib json encoder / decoder.
-					return hasAnyFieldBeenSet, fmt.Errorf("type '%s' isn't supported and won't ever be: %w", structField.Name, yagcl.ErrUnsupportedFieldType)
-				}
-			case reflect.Int64:
-				{
-					if dataType == jsonparser.String {
-						if stringValue, err := jsonparser.ParseString(valueBytes); err == nil {
-							// Since there are no constants for alias / struct types, we have
-							// to an additional check with custom parsing, since durations
-							// also contain a duration unit, such as "s" for seconds.
-							if fieldType.AssignableTo(reflect.TypeOf(time.Duration(0))) {
-								var errParse error
-								value, errParse = time.ParseDuration(stringValue)
-								if errParse != nil {
-									return hasAnyFieldBeenSet, fmt.Errorf("value '%s' isn't parsable as an 'time.Duration' for field '%s': %w", stringValue, structField.Name, yagcl.ErrParseValue)
-								}
-
-								value = reflect.ValueOf(value).Convert(fieldType).Interface()
-								// Parse successful, default path not needed.
-								break
+			value = structValue.Interface()
+		case reflect.Complex64, reflect.Complex128:
+			{
+				// Complex isn't supported, as for example it also isn't supported
+				// by the stdlib json encoder / decoder.
+				return false, fmt.Errorf("type '%s' isn't supported and won't ever be: %w", structField.Name, yagcl.ErrUnsupportedFieldType)
+			}
+		case reflect.Int64:
+			{
+				if dataType == jsonparser.String {
+					if stringValue, err := jsonparser.ParseString(valueBytes); err == nil {
+						// Since there are no constants for alias / struct types, we have
+						// to an additional check with custom parsing, since durations
+						// also contain a duration unit, such as "s" for seconds.
+						if fieldType.AssignableTo(reflect.TypeOf(time.Duration(0))) {
+							var errParse error
+							value, errParse = time.ParseDuration(stringValue)
+							if errParse != nil {
+								return false, fmt.Errorf("value '%s' isn't parsable as an 'time.Duration' for field '%s': %w", stringValue, structField.Name, yagcl.ErrParseValue)
 							}
+
+							value = reflect.ValueOf(value).Convert(fieldType).Interface()
+							// Parse successful, default path not needed.
+							break
 						}
 					}
 				}
-				// Since we seem to just have a normal int64 (or other alias type), we
-				// want to proceed treating it as a normal int, which is why we
-				// fallthrough.
-				fallthrough
-			default:
-				{
-					value = reflect.New(fieldType).Interface()
-					err = json.Unmarshal(valueBytes, &value)
-					if err != nil {
-						return hasAnyFieldBeenSet, newUnmarshalError(jsonPath, err)
-					}
+			}
+			// Since we seem to just have a normal int64 (or other alias type), we
+			// want to proceed treating it as a normal int, which is why we
+			// fallthrough.
+			fallthrough
+		default:
+			{
+				// jsonparser strips the quotes from strings, which are
+				// required for types such as []byte, which are encoded as
+				// base64 strings.
+				if dataType == jsonparser.String {
+					valueBytes = append(append([]byte(`"`), valueBytes...), byte('"'))
+				}
+				value = reflect.New(fieldType).Interface()
+				if err := json.Unmarshal(valueBytes, &value); err != nil {
+					return false, newUnmarshalError(jsonPath, err)
 				}
 			}
 		}
+	}
 
-		hasAnyFieldBeenSet = true
-		// Make sure that we have neither a pointer, not type aliased type that is incorrect.
-		parsed := reflect.Indirect(reflect.ValueOf(value)).Convert(fieldType)
-		if fieldValue.Kind() == reflect.Pointer {
-			// Create as many values as we have pointers pointing to things.
-			var pointers []reflect.Value
-			lastPointer := reflect.New(fieldValue.Type().Elem())
-			pointers = append(pointers, lastPointer)
-			for lastPointer.Elem().Kind() == reflect.Pointer {
-				lastPointer = reflect.New(lastPointer.Elem().Type().Elem())
-				pointers = append(pointers, lastPointer)
-			}
+	// Make sure that we have neither a pointer, not type aliased type that is incorrect.
+	parsed := reflect.Indirect(reflect.ValueOf(value)).Convert(fieldType)
+	if err := s.validate(structField, jsonPath, valueOffset, parsed); err != nil {
+		return false, err
+	}
 
-			pointers[len(pointers)-1].Elem().Set(parsed)
-			for i := len(pointers) - 2; i >= 0; i-- {
-				pointers[i].Elem().Set(pointers[i+1])
-			}
-			fieldValue.Set(pointers[0])
-		} else {
-			fieldValue.Set(parsed)
+	// Structs are tracked via their fields.
+	if fieldType.Kind() != reflect.Struct || customUnmarshalApplied {
+		s.recordProvenance(jsonPath, valueOffset)
+	}
+	setFieldValue(fieldValue, parsed)
+	if !reachable {
+		fieldByIndexAlloc(structValue, field.index).Set(fieldValue)
+	}
+	return true, nil
+}
+
+// applyDefault sets the value defined via the `default` tag, if the field
+// hasn't been set yet. Fields are considered set if a JSON source parsed
+// previously during the same load has set them, even to their zero value.
+// Non-zero values set by other sources or by the caller are kept as well.
+// The value is decoded just like a value from the document. Non-pointer
+// structs are always visited, as their fields might define defaults as well.
+func (s *jsonSourceImpl) applyDefault(parsingCompanion yagcl.ParsingCompanion, field boundField, jsonPath []string, structValue reflect.Value) (bool, error) {
+	fieldType := extractNonPointerFieldType(field.structField.Type)
+	literal, hasDefault := field.structField.Tag.Lookup("default")
+	if !hasDefault {
+		if field.structField.Type.Kind() == reflect.Struct && !hasCustomUnmarshaler(fieldType) {
+			return s.setField(parsingCompanion, field, jsonPath, structValue, []byte("{}"), jsonparser.Object, -1)
 		}
+		return false, nil
 	}
 
-	return hasAnyFieldBeenSet, nil
+	if s.setByPreviousSource(parsingCompanion, formatPath(jsonPath)) {
+		return false, nil
+	}
+	if fieldValue, reachable := fieldByIndex(structValue, field.index); reachable && !fieldValue.IsZero() {
+		return false, nil
+	}
+
+	// The default value is never wrapped in a string.
+	field.tag.asString = false
+	valueBytes, dataType := parseDefaultLiteral(literal, fieldType)
+	hasBeenSet, err := s.setField(parsingCompanion, field, jsonPath, structValue, valueBytes, dataType, -1)
+	if err != nil {
+		return hasBeenSet, fmt.Errorf("invalid default value '%s': %w", literal, err)
+	}
+	return hasBeenSet, nil
+}
+
+// parseDefaultLiteral turns the value of a `default` tag into a JSON value.
+// Literals for strings and types implementing encoding.TextUnmarshaler are
+// always treated as strings. For all other types, the literal is treated as
+// a string, unless it is valid JSON. This allows durations such as "30s",
+// as well as slices such as "[1,2,3]".
+func parseDefaultLiteral(literal string, fieldType reflect.Type) ([]byte, jsonparser.ValueType) {
+	isText := fieldType.Kind() == reflect.String ||
+		(reflect.PointerTo(fieldType).Implements(textUnmarshalerType) &&
+			!reflect.PointerTo(fieldType).Implements(jsonUnmarshalerType))
+	if !isText && json.Valid([]byte(literal)) {
+		if value, dataType, _, err := jsonparser.Get([]byte(literal)); err == nil {
+			return value, dataType
+		}
+	}
+
+	// jsonparser strips the quotes, but keeps escape sequences.
+	quoted, _ := json.Marshal(literal)
+	return quoted[1 : len(quoted)-1], jsonparser.String
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// hasCustomUnmarshaler checks whether the type implements json.Unmarshaler
+// or encoding.TextUnmarshaler.
+func hasCustomUnmarshaler(valueType reflect.Type) bool {
+	pointerType := reflect.PointerTo(valueType)
+	return pointerType.Implements(jsonUnmarshalerType) || pointerType.Implements(textUnmarshalerType)
+}
+
+// newStructTarget returns an addressable struct value that can be passed to
+// jsonSourceImpl.parse. If fieldValue already holds a struct, it is reused,
+// so that previously set values are preserved. Otherwise a temporary value
+// is created, which has to be assigned via setFieldValue.
+func newStructTarget(fieldValue reflect.Value, structType reflect.Type) reflect.Value {
+	target := fieldValue
+	for target.Kind() == reflect.Pointer {
+		if target.IsNil() {
+			return reflect.New(structType).Elem()
+		}
+		target = target.Elem()
+	}
+	return target
+}
+
+// setFieldValue assigns the given non-pointer value to the field, creating
+// all pointers required in case the field is a pointer (to a pointer ...).
+func setFieldValue(fieldValue, parsed reflect.Value) {
+	if fieldValue.Kind() != reflect.Pointer {
+		fieldValue.Set(parsed)
+		return
+	}
+
+	// Create as many values as we have pointers pointing to things.
+	var pointers []reflect.Value
+	lastPointer := reflect.New(fieldValue.Type().Elem())
+	pointers = append(pointers, lastPointer)
+	for lastPointer.Elem().Kind() == reflect.Pointer {
+		lastPointer = reflect.New(lastPointer.Elem().Type().Elem())
+		pointers = append(pointers, lastPointer)
+	}
+
+	pointers[len(pointers)-1].Elem().Set(parsed)
+	for i := len(pointers) - 2; i >= 0; i-- {
+		pointers[i].Elem().Set(pointers[i+1])
+	}
+	fieldValue.Set(pointers[0])
+}
+
+func (s *jsonSourceImpl) recordPresence(jsonPath []string, set bool) {
+	if set {
+		if s.loaded == nil {
+			s.loaded = make(map[string]bool)
+		}
+		s.loaded[formatPath(jsonPath)] = true
+	}
+	if s.presence != nil {
+		s.presence.record(jsonPath, set)
+	}
+}
+
+// setByPreviousSource reports whether a JSON source parsed before this one
+// during the current load has set the value at the given path. yagcl doesn't
+// share any state between the sources of a load, so the sources are looked
+// up in the loader, which is passed as the yagcl.ParsingCompanion. As the
+// loader parses its sources in order, the state of the sources preceding
+// this one always stems from the current load.
+func (s *jsonSourceImpl) setByPreviousSource(parsingCompanion yagcl.ParsingCompanion, path string) bool {
+	loader := reflect.Indirect(reflect.ValueOf(parsingCompanion))
+	if loader.Kind() != reflect.Struct {
+		return false
+	}
+	sources := loader.FieldByName("sources")
+	if sources.Kind() != reflect.Slice {
+		return false
+	}
+
+	var set bool
+	for index := 0; index < sources.Len(); index++ {
+		source := sources.Index(index).Elem()
+		if !source.IsValid() || source.Type() != reflect.TypeOf(s) {
+			continue
+		}
+		// Sources that aren't part of the loader can't know which sources
+		// have been parsed during the current load.
+		if source.Pointer() == reflect.ValueOf(s).Pointer() {
+			return set
+		}
+		set = set || source.Elem().FieldByName("loaded").MapIndex(reflect.ValueOf(path)).IsValid()
+	}
+	return false
+}
+
+func (s *jsonSourceImpl) recordProvenance(jsonPath []string, offset int) {
+	// Values without an offset, such as defaults, aren't part of the
+	// document.
+	if s.provenance != nil && offset >= 0 {
+		s.provenance.record(jsonPath, s.sourceName(), s.document, offset, s.encrypted[offset])
+	}
 }
 
 func newUnmarshalError(jsonPath []string, err error) error {
@@ -369,10 +998,9 @@ func newJsonparserError(jsonPath []string, err error) error {
 }
 
 func (s *jsonSourceImpl) extractJSONKey(parsingCompanion yagcl.ParsingCompanion, structField reflect.StructField) (string, error) {
-	// Custom tag
-	key := structField.Tag.Get(s.KeyTag())
-	if key != "" {
-		return strings.Split(key, ",")[0], nil
+	// Custom tag, options such as "omitempty" are irrelevant here.
+	if key := parseJSONTag(structField.Tag.Get(s.KeyTag())).name; key != "" {
+		return key, nil
 	}
 
 	// Fallback tag
@@ -381,10 +1009,71 @@ func (s *jsonSourceImpl) extractJSONKey(parsingCompanion yagcl.ParsingCompanion,
 		return key, nil
 	}
 
+	// Naming strategy
+	if s.keyNaming != nil {
+		if key := s.keyNaming(structField.Name); key != "" {
+			return key, nil
+		}
+	}
+
 	// No tag found
 	return "", fmt.Errorf("neither tag '%s' nor the standard tag '%s' have been set for field '%s': %w", s.KeyTag(), yagcl.DefaultKeyTagName, structField.Name, yagcl.ErrExportedFieldMissingKey)
 }
 
+// jsonTag represents the value of a `json` tag as defined by encoding/json.
+type jsonTag struct {
+	// name is the key of the field, which may be empty.
+	name string
+	// skip is set for `json:"-"`, the field is to be ignored completely.
+	skip bool
+	// omitEmpty only affects encoding. Empty values won't be written.
+	omitEmpty bool
+	// asString expects the value to be wrapped in a JSON string.
+	asString bool
+	// inline reads the fields of a struct from the parent object.
+	inline bool
+}
+
+func parseJSONTag(tag string) jsonTag {
+	// `json:"-,"` means that the key is "-".
+	if tag == "-" {
+		return jsonTag{skip: true}
+	}
+
+	parts := strings.Split(tag, ",")
+	result := jsonTag{name: parts[0]}
+	for _, option := range parts[1:] {
+		switch option {
+		case "omitempty":
+			result.omitEmpty = true
+		case "string":
+			result.asString = true
+		case "inline":
+			result.inline = true
+		}
+	}
+	return result
+}
+
+// supportsStringOption checks whether the option `json:",string"` can be
+// applied to the given type. Just like encoding/json, this is only the case
+// for strings, numbers and booleans. Durations already accept strings and are
+// therefore excluded.
+func supportsStringOption(fieldType reflect.Type) bool {
+	if fieldType.AssignableTo(reflect.TypeOf(time.Duration(0))) {
+		return false
+	}
+
+	switch fieldType.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
 func extractNonPointerFieldType(fieldType reflect.Type) reflect.Type {
 	if fieldType.Kind() != reflect.Pointer {
 		return fieldType
@@ -0,0 +1,241 @@
+package yagcl_json
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/buger/jsonparser"
+)
+
+// ErrAmbiguousKey is returned if more than one JSON key matches the key of a
+// single struct field. This can only happen if a KeyMatchingPolicy other than
+// KeyMatchExact is used.
+var ErrAmbiguousKey = errors.New("more than one JSON key matches the field key")
+
+// KeyMatchingPolicy defines how the keys found in a JSON document are
+// compared to the keys defined by the struct fields.
+type KeyMatchingPolicy int
+
+const (
+	// KeyMatchExact only accepts JSON keys that are equal to the field key.
+	// This is the default.
+	KeyMatchExact KeyMatchingPolicy = iota
+	// KeyMatchCaseInsensitive accepts JSON keys that are equal to the field
+	// key under Unicode case-folding, just like encoding/json does.
+	KeyMatchCaseInsensitive
+	// KeyMatchNormalized accepts JSON keys that are equal to the field key
+	// when ignoring case, underscores and hyphens. For example "field_a",
+	// "Field-A" and "fieldA" are all considered equal.
+	KeyMatchNormalized
+)
+
+// matches checks whether the given JSON key matches the key defined by a
+// struct field.
+func (policy KeyMatchingPolicy) matches(jsonKey, fieldKey string) bool {
+	switch policy {
+	case KeyMatchCaseInsensitive:
+		return strings.EqualFold(jsonKey, fieldKey)
+	case KeyMatchNormalized:
+		return strings.EqualFold(normalizeKey(jsonKey), normalizeKey(fieldKey))
+	default:
+		return jsonKey == fieldKey
+	}
+}
+
+func normalizeKey(key string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
+
+// KeyNamingStrategy derives a JSON key from the name of a Go struct field. It
+// is used for fields that don't define a key via tags. Returning an empty
+// string is treated as if no key could be derived.
+type KeyNamingStrategy func(fieldName string) string
+
+// SnakeCase is a KeyNamingStrategy turning "HTTPServerPort" into
+// "http_server_port".
+func SnakeCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitWords(fieldName), "_"))
+}
+
+// KebabCase is a KeyNamingStrategy turning "HTTPServerPort" into
+// "http-server-port".
+func KebabCase(fieldName string) string {
+	return strings.ToLower(strings.Join(splitWords(fieldName), "-"))
+}
+
+// CamelCase is a KeyNamingStrategy turning "HTTPServerPort" into
+// "httpServerPort".
+func CamelCase(fieldName string) string {
+	words := splitWords(fieldName)
+	for index, word := range words {
+		if index == 0 {
+			words[index] = strings.ToLower(word)
+		} else {
+			words[index] = capitalize(word)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// PascalCase is a KeyNamingStrategy turning "HTTPServerPort" into
+// "HttpServerPort".
+func PascalCase(fieldName string) string {
+	words := splitWords(fieldName)
+	for index, word := range words {
+		words[index] = capitalize(word)
+	}
+	return strings.Join(words, "")
+}
+
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+// splitWords splits a Go identifier into its words. Abbreviations are kept
+// together, so "HTTPServer" results in "HTTP" and "Server". Digits are part
+// of the preceding word and underscores are treated as separators.
+func splitWords(identifier string) []string {
+	var words []string
+	runes := []rune(identifier)
+	start := 0
+	for index := 0; index < len(runes); index++ {
+		current := runes[index]
+		if current == '_' {
+			if index > start {
+				words = append(words, string(runes[start:index]))
+			}
+			start = index + 1
+			continue
+		}
+
+		if index == start || !unicode.IsUpper(current) {
+			continue
+		}
+
+		previous := runes[index-1]
+		// "serverPort" or "port1Value"
+		startsWord := unicode.IsLower(previous) || unicode.IsDigit(previous)
+		// "HTTPServer", where "S" starts a new word.
+		if !startsWord && index+1 < len(runes) && unicode.IsLower(runes[index+1]) {
+			startsWord = true
+		}
+		if startsWord {
+			words = append(words, string(runes[start:index]))
+			start = index
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// jsonMember is a single key value pair of a JSON object.
+type jsonMember struct {
+	key      string
+	value    []byte
+	dataType jsonparser.ValueType
+	// offset is the start of the value relative to the object, including
+	// the quotes in case of strings.
+	offset int
+}
+
+// lookupKey searches the given object for a key matching fieldKey according
+// to the configured KeyMatchingPolicy. If no key matches, nil is returned.
+func (s *jsonSourceImpl) lookupKey(objectBytes []byte, fieldKey string) (*jsonMember, error) {
+	var match *jsonMember
+	err := jsonparser.ObjectEach(objectBytes, func(key, value []byte, dataType jsonparser.ValueType, endOffset int) error {
+		jsonKey := string(key)
+		if !s.keyMatching.matches(jsonKey, fieldKey) {
+			return nil
+		}
+
+		if match != nil {
+			// Repeating the exact same key is a different issue, which
+			// is handled by resolveDuplicateKeys. Otherwise the first
+			// occurrence is used, as jsonparser.Get would.
+			if match.key == jsonKey {
+				return nil
+			}
+			return fmt.Errorf("keys '%s' and '%s' both match '%s': %w", match.key, jsonKey, fieldKey, ErrAmbiguousKey)
+		}
+
+		offset := endOffset - len(value)
+		// jsonparser strips the quotes of strings.
+		if dataType == jsonparser.String {
+			offset -= 2
+		}
+		match = &jsonMember{key: jsonKey, value: value, dataType: dataType, offset: offset}
+		return nil
+	})
+	return match, err
+}
+
+// blankComments replaces all single line and multi line comments outside of
+// strings with whitespace. Line breaks are kept, so that all offsets stay
+// valid.
+func blankComments(data []byte) []byte {
+	var result []byte
+	var inString bool
+	for i := 0; i < len(data); i++ {
+		char := data[i]
+		if inString {
+			if char == '\\' {
+				i++
+			} else if char == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		if char == '"' {
+			inString = true
+			continue
+		}
+
+		if char != '/' || i+1 >= len(data) || (data[i+1] != '/' && data[i+1] != '*') {
+			continue
+		}
+
+		// Copy lazily, as most documents won't contain any comments.
+		if result == nil {
+			result = make([]byte, len(data))
+			copy(result, data)
+		}
+
+		multiline := data[i+1] == '*'
+		start := i
+		i += 2
+		for ; i < len(data); i++ {
+			if !multiline && (data[i] == '\n' || data[i] == '\r') {
+				break
+			}
+			if multiline && data[i] == '*' && i+1 < len(data) && data[i+1] == '/' {
+				i++
+				break
+			}
+		}
+
+		end := i
+		if end >= len(data) || !multiline {
+			// The line break itself isn't part of the comment.
+			end--
+		}
+		for j := start; j <= end && j < len(data); j++ {
+			if result[j] != '\n' && result[j] != '\r' {
+				result[j] = ' '
+			}
+		}
+	}
+
+	if result == nil {
+		return data
+	}
+	return result
+}
@@ -0,0 +1,196 @@
+package yagcl_json
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_KeyMatching_Exact(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"Field_A": "content a"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Empty(t, c.FieldA)
+	}
+}
+
+func Test_Parse_KeyMatching_CaseInsensitive(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a"`
+		FieldB struct {
+			FieldC int `key:"field_c"`
+		} `key:"field_b"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{
+				"Field_A": "content a",
+				"FIELD_B": {"fiELD_c": 1}
+			}`).
+			KeyMatching(KeyMatchCaseInsensitive)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "content a", c.FieldA)
+		assert.Equal(t, 1, c.FieldB.FieldC)
+	}
+
+	c = configuration{}
+	err = yagcl.New[configuration]().
+		Add(Source().
+			String(`{"fielda": "content a"}`).
+			KeyMatching(KeyMatchCaseInsensitive)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Empty(t, c.FieldA)
+	}
+}
+
+func Test_Parse_KeyMatching_Normalized(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a"`
+	}
+
+	for _, value := range []string{
+		`{"field_a": "content a"}`,
+		`{"Field-A": "content a"}`,
+		`{"fieldA": "content a"}`,
+		`{"FIELDA": "content a"}`,
+	} {
+		t.Run(value, func(t *testing.T) {
+			var c configuration
+			err := yagcl.New[configuration]().
+				Add(Source().String(value).KeyMatching(KeyMatchNormalized)).
+				Parse(&c)
+			if assert.NoError(t, err) {
+				assert.Equal(t, "content a", c.FieldA)
+			}
+		})
+	}
+}
+
+func Test_Parse_KeyMatching_Ambiguous(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{"field_a": "a", "Field_A": "b"}`).
+			KeyMatching(KeyMatchCaseInsensitive)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrAmbiguousKey)
+
+	err = yagcl.New[configuration]().
+		Add(Source().
+			String(`{"field_a": "a", "field-a": "b"}`).
+			KeyMatching(KeyMatchNormalized)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrAmbiguousKey)
+
+	// Both keys are different when matching exactly.
+	c = configuration{}
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"field_a": "a", "Field_A": "b"}`)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a", c.FieldA)
+	}
+}
+
+func Test_BlankComments(t *testing.T) {
+	for _, value := range [][2]string{
+		{`{}`, `{}`},
+		{"{// comment\n}", "{          \n}"},
+		{"{/* a\nb */}", "{    \n    }"},
+		{`{"a": "// no comment"}`, `{"a": "// no comment"}`},
+		{`{"a": "\"// no comment"}`, `{"a": "\"// no comment"}`},
+		{"{} // trailing", "{}            "},
+		{"{} /* unclosed", "{}            "},
+	} {
+		t.Run(value[0], func(t *testing.T) {
+			assert.Equal(t, value[1], string(blankComments([]byte(value[0]))))
+		})
+	}
+}
+
+func Test_KeyNamingStrategies(t *testing.T) {
+	for _, value := range []struct {
+		fieldName string
+		snake     string
+		kebab     string
+		camel     string
+		pascal    string
+	}{
+		{"A", "a", "a", "a", "A"},
+		{"FieldA", "field_a", "field-a", "fieldA", "FieldA"},
+		{"HTTPServerPort", "http_server_port", "http-server-port", "httpServerPort", "HttpServerPort"},
+		{"ServerURL", "server_url", "server-url", "serverUrl", "ServerUrl"},
+		{"Port1Value", "port1_value", "port1-value", "port1Value", "Port1Value"},
+		{"Already_Snake", "already_snake", "already-snake", "alreadySnake", "AlreadySnake"},
+	} {
+		t.Run(value.fieldName, func(t *testing.T) {
+			assert.Equal(t, value.snake, SnakeCase(value.fieldName))
+			assert.Equal(t, value.kebab, KebabCase(value.fieldName))
+			assert.Equal(t, value.camel, CamelCase(value.fieldName))
+			assert.Equal(t, value.pascal, PascalCase(value.fieldName))
+		})
+	}
+}
+
+func Test_Parse_KeyNaming(t *testing.T) {
+	type configuration struct {
+		FieldA     string
+		ServerPort int
+		Tagged     string `key:"explicit"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{
+				"field_a": "content a",
+				"server_port": 8080,
+				"explicit": "tagged"
+			}`).
+			KeyNaming(SnakeCase)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "content a", c.FieldA)
+		assert.Equal(t, 8080, c.ServerPort)
+		assert.Equal(t, "tagged", c.Tagged)
+	}
+}
+
+func Test_Parse_KeyNaming_Custom(t *testing.T) {
+	type configuration struct {
+		FieldA string
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{"x-FieldA": "content a"}`).
+			KeyNaming(func(fieldName string) string { return "x-" + fieldName })).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "content a", c.FieldA)
+	}
+
+	// Empty keys aren't accepted.
+	err = yagcl.New[configuration]().
+		Add(Source().
+			String(`{"": "content a"}`).
+			KeyNaming(func(string) string { return "" })).
+		Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrExportedFieldMissingKey)
+}
@@ -0,0 +1,164 @@
+package yagcl_json
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrLimitExceeded is wrapped by LimitError.
+var ErrLimitExceeded = errors.New("document exceeds a limit")
+
+// LimitError is returned if a document exceeds one of the limits defined via
+// JSONSourceOptionalSetup.MaxBytes, MaxDepth, MaxArrayLen or MaxStringLen.
+type LimitError struct {
+	// Source is the name of the source.
+	Source string
+	// Limit is the name of the exceeded limit, for example "MaxDepth".
+	Limit string
+	// Max is the configured value of the limit.
+	Max int64
+	// Line and Column define the position at which the limit has been
+	// exceeded. Both are 0 for MaxBytes.
+	Line, Column int
+}
+
+// Error implements error.Error.
+func (e *LimitError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("source '%s' exceeds %s of %d at %d:%d: %s", e.Source, e.Limit, e.Max, e.Line, e.Column, ErrLimitExceeded)
+	}
+	return fmt.Sprintf("source '%s' exceeds %s of %d: %s", e.Source, e.Limit, e.Max, ErrLimitExceeded)
+}
+
+// Unwrap allows checking for ErrLimitExceeded using errors.Is.
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// limits defines the limits for untrusted input. Zero means unlimited.
+type limits struct {
+	maxBytes     int64
+	maxDepth     int
+	maxArrayLen  int
+	maxStringLen int
+}
+
+func (s *jsonSourceImpl) newLimitError(limit string, max int64, document []byte, offset int) *LimitError {
+	limitErr := &LimitError{Source: s.sourceName(), Limit: limit, Max: max}
+	if document != nil {
+		limitErr.Line, limitErr.Column = position(document, offset)
+	}
+	return limitErr
+}
+
+// limitReader fails with a LimitError as soon as more than MaxBytes are read,
+// instead of reading the whole input first.
+type limitReader struct {
+	source    *jsonSourceImpl
+	reader    io.Reader
+	remaining int64
+}
+
+func (s *jsonSourceImpl) limitReader(reader io.Reader) io.Reader {
+	if s.limits.maxBytes <= 0 {
+		return reader
+	}
+	return &limitReader{source: s, reader: reader, remaining: s.limits.maxBytes}
+}
+
+func (r *limitReader) Read(buffer []byte) (int, error) {
+	// One additional byte is required to tell whether the limit has been
+	// exceeded or the input ends exactly at the limit.
+	if int64(len(buffer)) > r.remaining+1 {
+		buffer = buffer[:r.remaining+1]
+	}
+	read, err := r.reader.Read(buffer)
+	r.remaining -= int64(read)
+	if r.remaining < 0 {
+		return 0, r.source.newLimitError("MaxBytes", r.source.limits.maxBytes, nil, 0)
+	}
+	return read, err
+}
+
+// checkLimits checks the structure of the document against the configured
+// limits. This happens before the document is decoded, so that decoding
+// can't exhaust memory. The lengths of strings are measured in bytes,
+// including escape sequences, and apply to keys as well.
+func (s *jsonSourceImpl) checkLimits(document []byte) error {
+	if s.limits.maxBytes > 0 && int64(len(document)) > s.limits.maxBytes {
+		return s.newLimitError("MaxBytes", s.limits.maxBytes, nil, 0)
+	}
+	if s.limits.maxDepth <= 0 && s.limits.maxArrayLen <= 0 && s.limits.maxStringLen <= 0 {
+		return nil
+	}
+
+	type container struct {
+		array    bool
+		elements int
+		// expectValue is set at the start of arrays and after commas.
+		expectValue bool
+	}
+	var stack []container
+	countValue := func(offset int) error {
+		if len(stack) == 0 {
+			return nil
+		}
+		top := &stack[len(stack)-1]
+		if !top.array || !top.expectValue {
+			return nil
+		}
+		top.expectValue = false
+		top.elements++
+		if s.limits.maxArrayLen > 0 && top.elements > s.limits.maxArrayLen {
+			return s.newLimitError("MaxArrayLen", int64(s.limits.maxArrayLen), document, offset)
+		}
+		return nil
+	}
+
+	for index := 0; index < len(document); index++ {
+		switch char := document[index]; char {
+		case ' ', '\t', '\n', '\r', ':':
+		case ',':
+			if len(stack) > 0 {
+				stack[len(stack)-1].expectValue = true
+			}
+		case '{', '[':
+			if err := countValue(index); err != nil {
+				return err
+			}
+			stack = append(stack, container{array: char == '[', expectValue: char == '['})
+			if s.limits.maxDepth > 0 && len(stack) > s.limits.maxDepth {
+				return s.newLimitError("MaxDepth", int64(s.limits.maxDepth), document, index)
+			}
+		case '}', ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case '"':
+			if err := countValue(index); err != nil {
+				return err
+			}
+			start := index
+			for index++; index < len(document) && document[index] != '"'; index++ {
+				if document[index] == '\\' {
+					index++
+				}
+			}
+			// The quotes aren't part of the string.
+			if length := index - start - 1; s.limits.maxStringLen > 0 && length > s.limits.maxStringLen {
+				return s.newLimitError("MaxStringLen", int64(s.limits.maxStringLen), document, start)
+			}
+		default:
+			// Numbers and literals such as true and null.
+			if err := countValue(index); err != nil {
+				return err
+			}
+			for index+1 < len(document) && !isWhitespace(document[index+1]) &&
+				document[index+1] != ',' && document[index+1] != ']' && document[index+1] != '}' {
+				index++
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package yagcl_json
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+type limitsConfiguration struct {
+	Name   string `key:"name"`
+	Hosts  []any  `key:"hosts"`
+	Nested struct {
+		Value any `key:"value"`
+	} `key:"nested"`
+}
+
+func Test_Parse_Limits_Valid(t *testing.T) {
+	var c limitsConfiguration
+	err := yagcl.New[limitsConfiguration]().
+		Add(Source().String(`{"name": "abc", "hosts": [1, "a", [true, null]], "nested": {"value": [[]]}}`).
+			MaxBytes(100).
+			MaxDepth(4).
+			MaxArrayLen(3).
+			MaxStringLen(6)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "abc", c.Name)
+		assert.Len(t, c.Hosts, 3)
+	}
+}
+
+func Test_Parse_Limits_Exceeded(t *testing.T) {
+	for _, value := range []struct {
+		name     string
+		document string
+		limit    string
+		line     int
+		column   int
+	}{
+		{"depth", "{\n\"nested\": {\"value\": [[1]]}}", "MaxDepth", 2, 22},
+		{"array", `{"hosts": [1, 2, 3, 4]}`, "MaxArrayLen", 1, 21},
+		{"nested array", `{"hosts": [[1, 2], [1, 2, 3, 4]]}`, "MaxArrayLen", 1, 30},
+		{"string array", `{"hosts": ["a", "b", "c", "d"]}`, "MaxArrayLen", 1, 27},
+		{"string", `{"name": "abcdefg"}`, "MaxStringLen", 1, 10},
+		{"escaped string", `{"name": "ab\"\"cd"}`, "MaxStringLen", 1, 10},
+		{"key", `{"abcdefg": 1}`, "MaxStringLen", 1, 2},
+	} {
+		t.Run(value.name, func(t *testing.T) {
+			var c limitsConfiguration
+			err := yagcl.New[limitsConfiguration]().
+				Add(Source().String(value.document).
+					MaxDepth(3).
+					MaxArrayLen(3).
+					MaxStringLen(6)).
+				Parse(&c)
+
+			assert.ErrorIs(t, err, ErrLimitExceeded)
+			var limitErr *LimitError
+			if assert.True(t, errors.As(err, &limitErr)) {
+				assert.Equal(t, value.limit, limitErr.Limit)
+				assert.Equal(t, value.line, limitErr.Line)
+				assert.Equal(t, value.column, limitErr.Column)
+			}
+		})
+	}
+}
+
+// endlessReader returns an infinite amount of spaces.
+type endlessReader struct{}
+
+func (endlessReader) Read(buffer []byte) (int, error) {
+	for index := range buffer {
+		buffer[index] = ' '
+	}
+	return len(buffer), nil
+}
+
+func Test_Parse_MaxBytes(t *testing.T) {
+	var c limitsConfiguration
+	err := yagcl.New[limitsConfiguration]().
+		Add(Source().Reader(io.MultiReader(strings.NewReader(`{"name": "a"}`), endlessReader{})).MaxBytes(1024)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.EqualError(t, err, "source '<reader>' exceeds MaxBytes of 1024: document exceeds a limit")
+
+	err = yagcl.New[limitsConfiguration]().
+		Add(Source().String(`{"name": "abc"}`).MaxBytes(14)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	err = yagcl.New[limitsConfiguration]().
+		Add(Source().Path("./test.json").MaxBytes(10)).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+
+	// Exactly at the limit.
+	err = yagcl.New[limitsConfiguration]().
+		Add(Source().Reader(strings.NewReader(`{"name": "abc"}`)).MaxBytes(15)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "abc", c.Name)
+	}
+}
+
+func Test_LimitError_Error(t *testing.T) {
+	err := &LimitError{Source: "config.json", Limit: "MaxDepth", Max: 3, Line: 2, Column: 5}
+	assert.EqualError(t, err, "source 'config.json' exceeds MaxDepth of 3 at 2:5: document exceeds a limit")
+}
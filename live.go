@@ -0,0 +1,96 @@
+package yagcl_json
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Live holds the active configuration and allows swapping it, while any
+// number of goroutines read it. Reading is lock-free.
+type Live[T any] struct {
+	// value holds a *T. atomic.Pointer would require Go 1.19.
+	value atomic.Value
+
+	mutex       sync.Mutex
+	subscribers map[chan *T]struct{}
+}
+
+// NewLive creates a Live holding the given configuration.
+func NewLive[T any](configuration T) *Live[T] {
+	live := &Live[T]{subscribers: make(map[chan *T]struct{})}
+	live.value.Store(&configuration)
+	return live
+}
+
+// Load returns the active configuration. The configuration is shared
+// between all readers and therefore must not be modified.
+func (l *Live[T]) Load() *T {
+	return l.value.Load().(*T)
+}
+
+// Store makes the given configuration the active one and notifies all
+// subscribers.
+func (l *Live[T]) Store(configuration T) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.value.Store(&configuration)
+	for subscriber := range l.subscribers {
+		// Subscribers that haven't received the previous configuration yet
+		// only receive the latest one.
+		select {
+		case <-subscriber:
+		default:
+		}
+		subscriber <- &configuration
+	}
+}
+
+// Subscribe returns a channel that receives every configuration passed to
+// Store. Slow subscribers skip configurations that have already been
+// replaced again. Calling the returned function ends the subscription and
+// closes the channel.
+func (l *Live[T]) Subscribe() (<-chan *T, func()) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	subscriber := make(chan *T, 1)
+	l.subscribers[subscriber] = struct{}{}
+	var once sync.Once
+	return subscriber, func() {
+		once.Do(func() {
+			l.mutex.Lock()
+			defer l.mutex.Unlock()
+			delete(l.subscribers, subscriber)
+			close(subscriber)
+		})
+	}
+}
+
+// Live loads the configuration like Watch does, but stores all changes in
+// the returned Live. Errors occurring while reloading are passed to onError,
+// which may be nil.
+func (w *Watcher[T]) Live(ctx context.Context, onError func(error)) (*Live[T], error) {
+	var live *Live[T]
+	// live is assigned before the first change can be reported, as
+	// changes are only checked after the initial load.
+	ready := make(chan struct{})
+	initial, err := w.Watch(ctx, func(_, new T, err error) {
+		<-ready
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+		live.Store(new)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	live = NewLive(initial)
+	close(ready)
+	return live, nil
+}
@@ -0,0 +1,98 @@
+package yagcl_json
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Live(t *testing.T) {
+	live := NewLive(CommonConfig{Name: "a"})
+	assert.Equal(t, "a", live.Load().Name)
+
+	updates, unsubscribe := live.Subscribe()
+	live.Store(CommonConfig{Name: "b"})
+	assert.Equal(t, "b", live.Load().Name)
+	assert.Equal(t, "b", (<-updates).Name)
+
+	// Only the latest configuration is delivered to slow subscribers.
+	live.Store(CommonConfig{Name: "c"})
+	live.Store(CommonConfig{Name: "d"})
+	assert.Equal(t, "d", (<-updates).Name)
+
+	unsubscribe()
+	unsubscribe()
+	_, open := <-updates
+	assert.False(t, open)
+	live.Store(CommonConfig{Name: "e"})
+}
+
+func Test_Live_Concurrent(t *testing.T) {
+	live := NewLive(CommonConfig{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				live.Store(CommonConfig{Verbose: j%2 == 0})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			updates, unsubscribe := live.Subscribe()
+			defer unsubscribe()
+			for j := 0; j < 100; j++ {
+				_ = live.Load().Verbose
+				select {
+				case <-updates:
+				default:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_Watcher_Live(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"port": 1}`), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 10)
+	live, err := NewWatcher[watchConfiguration](path).
+		Interval(5*time.Millisecond).
+		Debounce(20*time.Millisecond).
+		Live(ctx, func(err error) { errs <- err })
+	require.NoError(t, err)
+	assert.Equal(t, 1, live.Load().Port)
+
+	updates, unsubscribe := live.Subscribe()
+	defer unsubscribe()
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"port": 2}`), 0o600))
+	select {
+	case update := <-updates:
+		assert.Equal(t, 2, update.Port)
+		assert.Equal(t, 2, live.Load().Port)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change has been reported")
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"port": -1}`), 0o600))
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, ErrValidationFailed)
+		assert.Equal(t, 2, live.Load().Port)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error has been reported")
+	}
+}
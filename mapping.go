@@ -0,0 +1,68 @@
+package yagcl_json
+
+import (
+	"github.com/Bios-Marcel/yagcl"
+)
+
+// MappingOption configures how functions working without a source, such as
+// GenerateSchema and Marshal, map struct fields to JSON keys. They should be
+// configured just like the loader and the source reading the files, so that
+// both agree on the keys.
+type MappingOption func(*mapping)
+
+// WithSource uses the key matching policy, key naming strategy and null
+// policy of the given source, for example:
+//
+//	source := Source().Path("config.json").KeyNaming(SnakeCase)
+//	schema, err := GenerateSchema[Configuration](WithSource(source))
+func WithSource(source JSONSourceOptionalSetup[*jsonSourceImpl]) MappingOption {
+	return func(m *mapping) {
+		if jsonSource, ok := source.(*jsonSourceImpl); ok {
+			m.source = jsonSource
+		}
+	}
+}
+
+// WithLoader uses the rules of the given loader for deciding which fields
+// are included and which keys they have, so that options such as
+// yagcl.YAGCL.InferFieldKeys and yagcl.YAGCL.AdditionalKeyTags are honored.
+func WithLoader[T any](loader yagcl.YAGCL[T]) MappingOption {
+	return func(m *mapping) {
+		if companion, ok := loader.(yagcl.ParsingCompanion); ok {
+			m.companion = companion
+		}
+	}
+}
+
+// WithProvenance makes MarshalRedacted treat values as secret, if the given
+// Provenance recorded them as decrypted from "ENC[...]" strings.
+func WithProvenance(provenance *Provenance) MappingOption {
+	return func(m *mapping) {
+		m.provenance = provenance
+	}
+}
+
+// mapping holds the source and the yagcl.ParsingCompanion used for mapping
+// struct fields to JSON keys. The source is only used for its options.
+type mapping struct {
+	source     *jsonSourceImpl
+	companion  yagcl.ParsingCompanion
+	provenance *Provenance
+}
+
+func newMapping(options []MappingOption) *mapping {
+	m := &mapping{
+		source:    &jsonSourceImpl{},
+		companion: standardCompanion(),
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// standardCompanion returns the yagcl.ParsingCompanion yagcl itself passes
+// to sources, for functions that don't have access to a yagcl instance.
+func standardCompanion() yagcl.ParsingCompanion {
+	return yagcl.New[struct{}]().(yagcl.ParsingCompanion)
+}
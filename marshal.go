@@ -0,0 +1,29 @@
+package yagcl_json
+
+import (
+	"io"
+)
+
+// Marshal encodes the given configuration struct, or pointer to one, as
+// indented JSON. Keys are resolved by the same rules Parse applies, so the
+// result can be read by the JSON source again. Fields tagged with
+// `ignore:"true"` or `json:"-"` are skipped, as well as empty fields with the
+// `json:",omitempty"` option, unless they define a `default` tag, which would
+// replace them when loading the output. Durations are written as strings
+// such as "5s", while json.Marshaler and encoding.TextMarshaler
+// implementations are used if present.
+func Marshal(configuration any, options ...MappingOption) ([]byte, error) {
+	encoder := newEncoder(options)
+	encoder.omitEmpty = true
+	return encoder.encode(configuration)
+}
+
+// Write writes the configuration to the given writer, as defined by Marshal.
+func Write(writer io.Writer, configuration any, options ...MappingOption) error {
+	data, err := Marshal(configuration, options...)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	return err
+}
@@ -0,0 +1,180 @@
+package yagcl_json
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+// level implements encoding.TextMarshaler and encoding.TextUnmarshaler.
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	return []byte(strings.Repeat("*", int(l))), nil
+}
+
+func (l *level) UnmarshalText(text []byte) error {
+	*l = level(len(text))
+	return nil
+}
+
+type marshalDatabase struct {
+	Host string `key:"host"`
+	Port int    `key:"port"`
+}
+
+type marshalConfiguration struct {
+	CommonConfig
+	Timeout  time.Duration     `key:"timeout"`
+	Level    level             `key:"level"`
+	IP       net.IP            `key:"ip"`
+	Count    int               `json:"count,string"`
+	Hosts    []string          `key:"hosts"`
+	Data     []byte            `key:"data"`
+	Labels   map[string]string `key:"labels"`
+	Ratio    **float64         `key:"ratio"`
+	Database *marshalDatabase  `key:"database"`
+	Optional string            `json:"optional,omitempty"`
+	Nil      *int              `key:"nil"`
+	Ignored  string            `key:"ignored" ignore:"true"`
+	Skipped  string            `json:"-"`
+}
+
+func newMarshalConfiguration() marshalConfiguration {
+	ratio := 0.5
+	ratioPointer := &ratio
+	c := marshalConfiguration{
+		Timeout:  5 * time.Second,
+		Level:    3,
+		IP:       net.IPv4(127, 0, 0, 1),
+		Count:    2,
+		Hosts:    []string{"a", "<b>"},
+		Data:     []byte("hi"),
+		Labels:   map[string]string{"b": "2", "a": "1"},
+		Ratio:    &ratioPointer,
+		Database: &marshalDatabase{Host: "localhost", Port: 5432},
+		Ignored:  "ignored",
+		Skipped:  "skipped",
+	}
+	c.Name = "service"
+	c.Verbose = true
+	return c
+}
+
+func Test_Marshal(t *testing.T) {
+	c := newMarshalConfiguration()
+	data, err := Marshal(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{
+  "name": "service",
+  "verbose": true,
+  "timeout": "5s",
+  "level": "***",
+  "ip": "127.0.0.1",
+  "count": "2",
+  "hosts": [
+    "a",
+    "<b>"
+  ],
+  "data": "aGk=",
+  "labels": {
+    "a": "1",
+    "b": "2"
+  },
+  "ratio": 0.5,
+  "database": {
+    "host": "localhost",
+    "port": 5432
+  },
+  "nil": null
+}
+`, string(data))
+	}
+}
+
+func Test_Marshal_RoundTrip(t *testing.T) {
+	c := newMarshalConfiguration()
+	c.Optional = "optional"
+
+	var buffer bytes.Buffer
+	if !assert.NoError(t, Write(&buffer, c)) {
+		return
+	}
+
+	var parsed marshalConfiguration
+	err := yagcl.New[marshalConfiguration]().
+		Add(Source().Reader(&buffer)).
+		Parse(&parsed)
+	if assert.NoError(t, err) {
+		c.Ignored = ""
+		c.Skipped = ""
+		assert.Equal(t, c, parsed)
+	}
+}
+
+func Test_Marshal_Empty(t *testing.T) {
+	type configuration struct {
+		Optional string `json:"optional,omitempty"`
+	}
+
+	data, err := Marshal(configuration{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "{}\n", string(data))
+	}
+}
+
+func Test_Marshal_OmitEmptyDefault(t *testing.T) {
+	type configuration struct {
+		Port    int    `json:"port,omitempty" default:"8080"`
+		Verbose bool   `json:"verbose,omitempty" default:"true"`
+		Name    string `json:"name,omitempty"`
+	}
+
+	data, err := Marshal(configuration{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "{\n  \"port\": 0,\n  \"verbose\": false\n}\n", string(data))
+
+	// Loading the output doesn't turn the zero values into the defaults.
+	var parsed configuration
+	err = yagcl.New[configuration]().
+		Add(Source().Bytes(data)).
+		Parse(&parsed)
+	if assert.NoError(t, err) {
+		assert.Equal(t, configuration{}, parsed)
+	}
+}
+
+func Test_Marshal_Options(t *testing.T) {
+	type configuration struct {
+		ServerPort int
+	}
+
+	source := Source().String(`{}`).KeyNaming(SnakeCase)
+	data, err := Marshal(configuration{ServerPort: 1}, WithSource(source))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "{\n  \"server_port\": 1\n}\n", string(data))
+	}
+
+	var parsed configuration
+	err = yagcl.New[configuration]().
+		Add(Source().Bytes(data).KeyNaming(SnakeCase)).
+		Parse(&parsed)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, parsed.ServerPort)
+	}
+}
+
+func Test_Marshal_NoStruct(t *testing.T) {
+	_, err := Marshal("string")
+	assert.ErrorIs(t, err, yagcl.ErrUnsupportedFieldType)
+
+	_, err = Marshal(nil)
+	assert.ErrorIs(t, err, yagcl.ErrUnsupportedFieldType)
+}
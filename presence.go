@@ -0,0 +1,65 @@
+package yagcl_json
+
+import (
+	"sort"
+	"strings"
+)
+
+// Presence records which fields have been set by a source. This allows
+// telling apart a field that has explicitly been set to its zero value and
+// a field that hasn't been set at all. Fields are identified by their JSON
+// path, which consists of the JSON keys joined by dots, for example
+// "database.port". The zero value is ready to use.
+type Presence struct {
+	fields map[string]bool
+}
+
+// IsSet checks whether the field with the given JSON path has been set by
+// the source. A field set to null only counts as set if NullReset is used.
+func (p *Presence) IsSet(path string) bool {
+	return p.fields[path]
+}
+
+// IsKnown checks whether the field with the given JSON path has been
+// visited by the source at all. Fields inside of structs that aren't part
+// of the document won't be visited.
+func (p *Presence) IsKnown(path string) bool {
+	_, known := p.fields[path]
+	return known
+}
+
+// Paths returns the JSON paths of all visited fields in alphabetical order.
+func (p *Presence) Paths() []string {
+	paths := make([]string, 0, len(p.fields))
+	for path := range p.fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// SetPaths returns the JSON paths of all fields that have been set in
+// alphabetical order.
+func (p *Presence) SetPaths() []string {
+	var paths []string
+	for _, path := range p.Paths() {
+		if p.fields[path] {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func (p *Presence) reset() {
+	p.fields = make(map[string]bool)
+}
+
+func (p *Presence) record(jsonPath []string, set bool) {
+	p.fields[formatPath(jsonPath)] = set
+}
+
+// formatPath turns a JSON path into its string representation, which is
+// the JSON keys joined by dots.
+func formatPath(jsonPath []string) string {
+	return strings.Join(jsonPath, ".")
+}
@@ -0,0 +1,89 @@
+package yagcl_json
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_TrackPresence(t *testing.T) {
+	type database struct {
+		Host string `key:"host"`
+		Port int    `key:"port"`
+	}
+	type configuration struct {
+		Retries  int       `key:"retries"`
+		Timeout  int       `key:"timeout"`
+		Name     *string   `key:"name"`
+		Database database  `key:"database"`
+		Cache    *database `key:"cache"`
+		Empty    database  `key:"empty"`
+	}
+
+	var presence Presence
+	c := configuration{Timeout: 30}
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{
+				"retries": 0,
+				"name": null,
+				"database": {"port": 5432},
+				"empty": {}
+			}`).
+			TrackPresence(&presence)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.True(t, presence.IsSet("retries"))
+		assert.False(t, presence.IsSet("timeout"))
+		assert.True(t, presence.IsKnown("timeout"))
+		assert.False(t, presence.IsSet("name"))
+		assert.True(t, presence.IsSet("database"))
+		assert.True(t, presence.IsSet("database.port"))
+		assert.False(t, presence.IsSet("database.host"))
+		assert.False(t, presence.IsSet("cache"))
+		assert.False(t, presence.IsKnown("cache.port"))
+		assert.False(t, presence.IsSet("empty"))
+
+		assert.Equal(t, []string{"database", "database.port", "retries"}, presence.SetPaths())
+		assert.Equal(t, []string{
+			"cache", "database", "database.host", "database.port",
+			"empty", "empty.host", "empty.port", "name", "retries", "timeout",
+		}, presence.Paths())
+	}
+}
+
+func Test_Parse_TrackPresence_NullReset(t *testing.T) {
+	type configuration struct {
+		Name *string `key:"name"`
+	}
+
+	var presence Presence
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{"name": null}`).
+			Null(NullReset).
+			TrackPresence(&presence)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.True(t, presence.IsSet("name"))
+	}
+}
+
+func Test_Parse_TrackPresence_Reset(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a"`
+		FieldB string `key:"field_b"`
+	}
+
+	var presence Presence
+	var c configuration
+	assert.NoError(t, yagcl.New[configuration]().
+		Add(Source().String(`{"field_a": "a"}`).TrackPresence(&presence)).
+		Parse(&c))
+	assert.NoError(t, yagcl.New[configuration]().
+		Add(Source().String(`{"field_b": "b"}`).TrackPresence(&presence)).
+		Parse(&c))
+	assert.Equal(t, []string{"field_b"}, presence.SetPaths())
+}
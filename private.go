@@ -0,0 +1,36 @@
+package yagcl_json
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// ErrNotPrivate is returned if RequirePrivate is used and the file can be
+// accessed by users other than the current user.
+var ErrNotPrivate = errors.New("file isn't private")
+
+// checkMode makes sure that neither the group nor others have any
+// permissions for the file, just like ssh does for private keys.
+func checkMode(path string, mode fs.FileMode) error {
+	if mode.Perm()&0o077 == 0 {
+		return nil
+	}
+
+	var bits []string
+	for _, class := range []struct {
+		name  string
+		shift uint
+	}{{"group", 3}, {"others", 0}} {
+		for _, permission := range []struct {
+			name string
+			bit  fs.FileMode
+		}{{"readable", 0o4}, {"writable", 0o2}, {"executable", 0o1}} {
+			if mode.Perm()&(permission.bit<<class.shift) != 0 {
+				bits = append(bits, class.name+" "+permission.name)
+			}
+		}
+	}
+	return fmt.Errorf("file '%s' has mode %s (%s): %w", path, mode.Perm(), strings.Join(bits, ", "), ErrNotPrivate)
+}
@@ -0,0 +1,15 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package yagcl_json
+
+import (
+	"fmt"
+	"os"
+)
+
+// checkPrivate always fails, as permissions and ownership can't be checked
+// reliably on this platform. For example, the permissions reported on
+// Windows don't reflect its ACLs.
+func checkPrivate(path string, _ *os.File) error {
+	return fmt.Errorf("permissions of file '%s' can't be checked on this platform: %w", path, ErrNotPrivate)
+}
@@ -0,0 +1,26 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package yagcl_json
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// checkPrivate makes sure that the file is owned by the current user and not
+// accessible by anyone else.
+func checkPrivate(path string, file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if err := checkMode(path, info.Mode()); err != nil {
+		return err
+	}
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && int(stat.Uid) != os.Getuid() {
+		return fmt.Errorf("file '%s' is owned by user %d instead of the current user %d: %w", path, stat.Uid, os.Getuid(), ErrNotPrivate)
+	}
+	return nil
+}
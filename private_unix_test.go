@@ -0,0 +1,84 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package yagcl_json
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePrivateTestFile(t *testing.T, mode os.FileMode) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"name": "a"}`), mode))
+	// The umask might have removed some bits.
+	require.NoError(t, os.Chmod(path, mode))
+	return path
+}
+
+func Test_Parse_RequirePrivate(t *testing.T) {
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Path(writePrivateTestFile(t, 0o600)).RequirePrivate()).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a", c.Name)
+	}
+}
+
+func Test_Parse_RequirePrivate_Mode(t *testing.T) {
+	for _, value := range []struct {
+		mode    os.FileMode
+		message string
+	}{
+		{0o640, "-rw-r----- (group readable)"},
+		{0o604, "-rw----r-- (others readable)"},
+		{0o672, "-rw-rwx-w- (group readable, group writable, group executable, others writable)"},
+	} {
+		t.Run(value.mode.String(), func(t *testing.T) {
+			path := writePrivateTestFile(t, value.mode)
+
+			var c CommonConfig
+			err := yagcl.New[CommonConfig]().
+				Add(Source().Path(path).RequirePrivate()).
+				Parse(&c)
+			assert.ErrorIs(t, err, ErrNotPrivate)
+			assert.ErrorContains(t, err, value.message)
+			assert.Empty(t, c.Name)
+
+			// Without the option, the file is loaded.
+			err = yagcl.New[CommonConfig]().
+				Add(Source().Path(path)).
+				Parse(&c)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_Parse_RequirePrivate_Owner(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("changing the owner of a file requires root")
+	}
+
+	path := writePrivateTestFile(t, 0o600)
+	require.NoError(t, os.Chown(path, 65534, 65534))
+
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Path(path).RequirePrivate()).
+		Parse(&c)
+	assert.ErrorIs(t, err, ErrNotPrivate)
+	assert.ErrorContains(t, err, "owned by user 65534")
+}
+
+func Test_Parse_RequirePrivate_Missing(t *testing.T) {
+	var c CommonConfig
+	err := yagcl.New[CommonConfig]().
+		Add(Source().Path(filepath.Join(t.TempDir(), "missing.json")).RequirePrivate()).
+		Parse(&c)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,125 @@
+package yagcl_json
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Origin describes where a value has been read from.
+type Origin struct {
+	// Source is the name of the source, see JSONSourceOptionalSetup.Name.
+	Source string
+	// Path is the JSON path of the value, for example "database.port".
+	Path string
+	// Offset is the byte offset of the value in the document.
+	Offset int
+	// Line is the line of the value in the document, starting at 1.
+	Line int
+	// Column is the column of the value in the document, starting at 1.
+	// Multi-byte characters count as a single column.
+	Column int
+	// Encrypted is set for values that have been decrypted from an
+	// "ENC[...]" string. MarshalRedacted treats these values as secret, see
+	// WithProvenance.
+	Encrypted bool
+}
+
+// String returns the origin in the format "source:line:column".
+func (o Origin) String() string {
+	return fmt.Sprintf("%s:%d:%d", o.Source, o.Line, o.Column)
+}
+
+// Provenance records the origin of each value set by one or more sources.
+// If multiple sources set the same value, the source parsed last wins, as
+// its value is the one ending up in the configuration. The zero value is
+// ready to use.
+type Provenance struct {
+	origins map[string]Origin
+}
+
+// Lookup returns the origin of the value with the given JSON path.
+func (p *Provenance) Lookup(path string) (Origin, bool) {
+	origin, ok := p.origins[path]
+	return origin, ok
+}
+
+// Origins returns a copy of all recorded origins, using the JSON paths as
+// keys.
+func (p *Provenance) Origins() map[string]Origin {
+	origins := make(map[string]Origin, len(p.origins))
+	for path, origin := range p.origins {
+		origins[path] = origin
+	}
+	return origins
+}
+
+// Explain returns a human readable listing of all recorded origins, one
+// value per line, sorted by JSON path. For example:
+//
+//	database.port: config.json:3:12
+func (p *Provenance) Explain() string {
+	paths := make([]string, 0, len(p.origins))
+	for path := range p.origins {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var builder strings.Builder
+	for _, path := range paths {
+		fmt.Fprintf(&builder, "%s: %s\n", path, p.origins[path])
+	}
+	return builder.String()
+}
+
+// Reset removes all recorded origins.
+func (p *Provenance) Reset() {
+	p.origins = nil
+}
+
+func (p *Provenance) record(jsonPath []string, source string, document []byte, offset int, encrypted bool) {
+	if p.origins == nil {
+		p.origins = make(map[string]Origin)
+	}
+
+	line, column := position(document, offset)
+	path := formatPath(jsonPath)
+	p.origins[path] = Origin{
+		Source:    source,
+		Path:      path,
+		Offset:    offset,
+		Line:      line,
+		Column:    column,
+		Encrypted: encrypted,
+	}
+}
+
+// forget removes all origins recorded for the source with the given name, so
+// that values the source doesn't set anymore aren't reported after parsing
+// it again.
+func (p *Provenance) forget(source string) {
+	for path, origin := range p.origins {
+		if origin.Source == source {
+			delete(p.origins, path)
+		}
+	}
+}
+
+// position calculates the line and column of the given byte offset. Both
+// start at 1.
+func position(document []byte, offset int) (line, column int) {
+	if offset > len(document) {
+		offset = len(document)
+	}
+
+	line = 1
+	lineStart := 0
+	for index := 0; index < offset; index++ {
+		if document[index] == '\n' {
+			line++
+			lineStart = index + 1
+		}
+	}
+	return line, utf8.RuneCount(document[lineStart:offset]) + 1
+}
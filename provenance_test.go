@@ -0,0 +1,107 @@
+package yagcl_json
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_TrackProvenance(t *testing.T) {
+	type database struct {
+		Host string `key:"host"`
+		Port int    `key:"port"`
+	}
+	type configuration struct {
+		Name     string   `key:"name"`
+		Database database `key:"database"`
+	}
+
+	var provenance Provenance
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String("{\n" +
+				"  \"name\": \"äöü\", \"database\": {\n" +
+				"    \"host\": \"localhost\",\n" +
+				"    \"port\": 5432\n" +
+				"  }\n" +
+				"}").
+			Name("base.json").
+			TrackProvenance(&provenance)).
+		Add(Source().
+			String(`{"database": {"port": 5433}}`).
+			Name("override.json").
+			TrackProvenance(&provenance)).
+		AllowOverride().
+		Parse(&c)
+	if assert.NoError(t, err) {
+		origin, ok := provenance.Lookup("name")
+		if assert.True(t, ok) {
+			assert.Equal(t, Origin{Source: "base.json", Path: "name", Offset: 12, Line: 2, Column: 11}, origin)
+		}
+		origin, ok = provenance.Lookup("database.host")
+		if assert.True(t, ok) {
+			assert.Equal(t, "base.json:3:13", origin.String())
+		}
+		origin, ok = provenance.Lookup("database.port")
+		if assert.True(t, ok) {
+			assert.Equal(t, "override.json:1:23", origin.String())
+		}
+		_, ok = provenance.Lookup("database")
+		assert.False(t, ok)
+
+		assert.Equal(t, "database.host: base.json:3:13\n"+
+			"database.port: override.json:1:23\n"+
+			"name: base.json:2:11\n", provenance.Explain())
+		assert.Len(t, provenance.Origins(), 3)
+
+		provenance.Reset()
+		assert.Empty(t, provenance.Explain())
+	}
+}
+
+func Test_Parse_TrackProvenance_DefaultName(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a"`
+	}
+
+	var provenance Provenance
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().Path("./test.json").TrackProvenance(&provenance)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		origin, _ := provenance.Lookup("field_a")
+		assert.Equal(t, "./test.json:3:16", origin.String())
+	}
+
+	provenance.Reset()
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"field_a": "a"}`).TrackProvenance(&provenance)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		origin, _ := provenance.Lookup("field_a")
+		assert.Equal(t, "<bytes>:1:13", origin.String())
+	}
+}
+
+func Test_Parse_TrackProvenance_Defaults(t *testing.T) {
+	type configuration struct {
+		FieldA string `key:"field_a" default:"a"`
+		FieldB string `key:"field_b"`
+	}
+
+	var provenance Provenance
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"field_b": "b"}`).TrackProvenance(&provenance)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a", c.FieldA)
+		_, ok := provenance.Lookup("field_a")
+		assert.False(t, ok)
+		_, ok = provenance.Lookup("field_b")
+		assert.True(t, ok)
+	}
+}
@@ -0,0 +1,42 @@
+package yagcl_json
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces the values of secret fields in the output of
+// MarshalRedacted and Diff.
+const Redacted = "[REDACTED]"
+
+// MarshalRedacted is like Marshal, but replaces the values of all fields
+// tagged with `secret:"true"` with Redacted, so that the effective
+// configuration can be logged without leaking credentials. Secrets holding
+// their zero value are written as they are, as they don't contain anything
+// worth hiding, but hint at missing configuration. Secret structs are
+// redacted as a whole. Note that fields of structs within slices and maps
+// are encoded via encoding/json and therefore can't be redacted.
+//
+// Values decrypted from "ENC[...]" strings are treated as secrets as well,
+// if the Provenance they have been recorded in is passed via WithProvenance.
+func MarshalRedacted(configuration any, options ...MappingOption) ([]byte, error) {
+	encoder := newEncoder(options)
+	encoder.omitEmpty = true
+	encoder.redact = true
+	return encoder.encode(configuration)
+}
+
+// isEncrypted checks whether the value at the given path has been decrypted
+// from an "ENC[...]" string, according to the Provenance of the mapping.
+func (m *mapping) isEncrypted(jsonPath []string) bool {
+	if m.provenance == nil {
+		return false
+	}
+	origin, ok := m.provenance.Lookup(formatPath(jsonPath))
+	return ok && origin.Encrypted
+}
+
+// isSecret checks whether the field is tagged with `secret:"true"`.
+func isSecret(structField reflect.StructField) bool {
+	return strings.EqualFold(structField.Tag.Get("secret"), "true")
+}
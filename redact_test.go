@@ -0,0 +1,95 @@
+package yagcl_json
+
+import (
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MarshalRedacted(t *testing.T) {
+	type credentials struct {
+		User     string `key:"user"`
+		Password string `key:"password" secret:"true"`
+	}
+	type configuration struct {
+		Name     string       `key:"name"`
+		Token    string       `key:"token" secret:"true"`
+		Empty    string       `key:"empty" secret:"TRUE"`
+		Port     *int         `key:"port" secret:"true"`
+		Database credentials  `key:"database" secret:"true"`
+		Nested   *credentials `key:"nested"`
+		Other    string       `key:"other" secret:"false"`
+	}
+
+	port := 5432
+	c := configuration{
+		Name:     "service",
+		Token:    "abc",
+		Port:     &port,
+		Database: credentials{User: "user", Password: "password"},
+		Nested:   &credentials{User: "user", Password: "password"},
+		Other:    "visible",
+	}
+	data, err := MarshalRedacted(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{
+  "name": "service",
+  "token": "[REDACTED]",
+  "empty": "",
+  "port": "[REDACTED]",
+  "database": "[REDACTED]",
+  "nested": {
+    "user": "user",
+    "password": "[REDACTED]"
+  },
+  "other": "visible"
+}
+`, string(data))
+	}
+
+	// Marshal itself doesn't redact anything.
+	data, err = Marshal(&c)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `"token": "abc"`)
+	}
+}
+
+func Test_MarshalRedacted_Encrypted(t *testing.T) {
+	aesgcm, err := NewAESGCM(testEncryptionKey)
+	require.NoError(t, err)
+	password, err := aesgcm.EncryptValue("secret")
+	require.NoError(t, err)
+	port, err := aesgcm.EncryptValue("5432")
+	require.NoError(t, err)
+
+	var provenance Provenance
+	var c encryptionConfiguration
+	err = yagcl.New[encryptionConfiguration]().
+		Add(Source().
+			String(`{"user": "admin", "password": "` + password + `", "port": "` + port + `"}`).
+			Decrypt(aesgcm).
+			TrackProvenance(&provenance)).
+		Parse(&c)
+	require.NoError(t, err)
+
+	origin, ok := provenance.Lookup("password")
+	if assert.True(t, ok) {
+		assert.True(t, origin.Encrypted)
+	}
+	origin, ok = provenance.Lookup("user")
+	if assert.True(t, ok) {
+		assert.False(t, origin.Encrypted)
+	}
+
+	data, err := MarshalRedacted(&c, WithProvenance(&provenance))
+	if assert.NoError(t, err) {
+		assert.Equal(t, `{
+  "user": "admin",
+  "password": "[REDACTED]",
+  "port": "[REDACTED]"
+}
+`, string(data))
+	}
+}
@@ -0,0 +1,98 @@
+package yagcl_json
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/buger/jsonparser"
+)
+
+// RequiredError is returned if required fields are missing from the
+// document or are null. It contains all affected JSON paths, so all of them
+// can be fixed at once. It wraps yagcl.ErrValueNotSet.
+type RequiredError struct {
+	// Missing contains the JSON paths of all required keys that aren't
+	// present in the document.
+	Missing []string
+	// Null contains the JSON paths of all required keys that are present in
+	// the document, but have the value null.
+	Null []string
+}
+
+// Error implements error.Error.
+func (e *RequiredError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing required keys: %s", strings.Join(e.Missing, ", ")))
+	}
+	if len(e.Null) > 0 {
+		problems = append(problems, fmt.Sprintf("required keys are null: %s", strings.Join(e.Null, ", ")))
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(problems, "; "), yagcl.ErrValueNotSet)
+}
+
+// Unwrap allows checking for yagcl.ErrValueNotSet using errors.Is.
+func (e *RequiredError) Unwrap() error {
+	return yagcl.ErrValueNotSet
+}
+
+func (e *RequiredError) addMissing(path string) {
+	if !contains(e.Missing, path) {
+		e.Missing = append(e.Missing, path)
+	}
+}
+
+func (e *RequiredError) addNull(path string) {
+	if !contains(e.Null, path) {
+		e.Null = append(e.Null, path)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, existing := range values {
+		if existing == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isRequired checks whether the field has the tag `required:"true"`.
+// Required fields inside of nested struct pointers are only checked if the
+// struct is present in the document.
+func isRequired(structField reflect.StructField) bool {
+	return strings.EqualFold(structField.Tag.Get("required"), "true")
+}
+
+// checkRequiredPaths checks the paths defined via
+// JSONSourceOptionalSetup.Required against the document.
+func (s *jsonSourceImpl) checkRequiredPaths(document []byte) error {
+	for _, path := range s.required {
+		value, dataType, found := document, jsonparser.Object, true
+		for _, key := range strings.Split(path, ".") {
+			if dataType != jsonparser.Object {
+				found = false
+				break
+			}
+
+			member, err := s.lookupKey(value, key)
+			if err != nil {
+				return fmt.Errorf("error accessing required json field '%s': %w", path, err)
+			}
+			if member == nil {
+				found = false
+				break
+			}
+			value, dataType = member.value, member.dataType
+		}
+
+		if !found {
+			s.missingRequired.addMissing(path)
+		} else if dataType == jsonparser.Null {
+			s.missingRequired.addNull(path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package yagcl_json
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_Required(t *testing.T) {
+	type database struct {
+		Host string `key:"host" required:"true"`
+		Port int    `key:"port" required:"true"`
+	}
+	type configuration struct {
+		Name     string    `key:"name" required:"true"`
+		Retries  *int      `key:"retries" required:"true"`
+		Database database  `key:"database"`
+		Cache    *database `key:"cache"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"retries": null, "database": {"port": null}}`)).
+		Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrValueNotSet)
+	var requiredErr *RequiredError
+	if assert.True(t, errors.As(err, &requiredErr)) {
+		assert.Equal(t, []string{"name", "database.host"}, requiredErr.Missing)
+		assert.Equal(t, []string{"retries", "database.port"}, requiredErr.Null)
+		assert.Equal(t, "missing required keys: name, database.host; "+
+			"required keys are null: retries, database.port: "+
+			yagcl.ErrValueNotSet.Error(), requiredErr.Error())
+	}
+
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{
+			"name": "service",
+			"retries": 0,
+			"database": {"host": "localhost", "port": 5432}
+		}`)).
+		Parse(&c)
+	assert.NoError(t, err)
+}
+
+func Test_Parse_Required_NestedPointer(t *testing.T) {
+	type database struct {
+		Host string `key:"host" required:"true"`
+	}
+	type configuration struct {
+		Database *database `key:"database"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{}`)).
+		Parse(&c)
+	assert.NoError(t, err)
+
+	err = yagcl.New[configuration]().
+		Add(Source().String(`{"database": {}}`)).
+		Parse(&c)
+	var requiredErr *RequiredError
+	if assert.True(t, errors.As(err, &requiredErr)) {
+		assert.Equal(t, []string{"database.host"}, requiredErr.Missing)
+		assert.Empty(t, requiredErr.Null)
+	}
+}
+
+func Test_Parse_Required_Paths(t *testing.T) {
+	type configuration struct {
+		Name string `key:"name" required:"true"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().
+			String(`{"db": {"host": null, "port": 1}, "other": 1}`).
+			Required("db.host", "db.port", "db.user", "other.field", "name")).
+		Parse(&c)
+	var requiredErr *RequiredError
+	if assert.True(t, errors.As(err, &requiredErr)) {
+		// The missing name is only reported once.
+		assert.Equal(t, []string{"name", "db.user", "other.field"}, requiredErr.Missing)
+		assert.Equal(t, []string{"db.host"}, requiredErr.Null)
+	}
+}
+
+func Test_Parse_Required_SourceNotFound(t *testing.T) {
+	type configuration struct {
+		Name string `key:"name" required:"true"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().Path("./doesntexist.json").Required("name")).
+		Parse(&c)
+	assert.NoError(t, err)
+}
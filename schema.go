@@ -0,0 +1,517 @@
+package yagcl_json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/buger/jsonparser"
+)
+
+// ErrSchemaViolation is wrapped by SchemaError and returned if a document
+// doesn't conform to the schema passed to JSONSourceOptionalSetup.Schema.
+var ErrSchemaViolation = errors.New("document violates schema")
+
+// ErrInvalidSchema is returned if the schema passed to
+// JSONSourceOptionalSetup.Schema can't be used.
+var ErrInvalidSchema = errors.New("invalid JSON schema")
+
+// SchemaViolation describes a single location in a document violating the
+// schema.
+type SchemaViolation struct {
+	// Pointer is the JSON Pointer (RFC 6901) of the violating value, for
+	// example "/database/port". The document itself is "".
+	Pointer string
+	// Message describes the violation.
+	Message string
+}
+
+// String returns the violation in the format "pointer: message".
+func (v SchemaViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Pointer, v.Message)
+}
+
+// SchemaError contains all violations found while validating a document
+// against a schema. It wraps ErrSchemaViolation.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+// Error implements error.Error.
+func (e *SchemaError) Error() string {
+	violations := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		violations = append(violations, fmt.Sprintf("'%s' %s", violation.Pointer, violation.Message))
+	}
+	return fmt.Sprintf("%s: %s", strings.Join(violations, "; "), ErrSchemaViolation)
+}
+
+// Unwrap allows checking for ErrSchemaViolation using errors.Is.
+func (e *SchemaError) Unwrap() error {
+	return ErrSchemaViolation
+}
+
+// validateSchema validates the document against a subset of JSON Schema
+// draft 2020-12. Supported are boolean schemas, as well as the keywords
+// type, enum, const, required, properties, patternProperties,
+// additionalProperties, items, minItems, maxItems, minLength, maxLength,
+// pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum, allOf,
+// anyOf and $ref, as long as the reference points into the schema itself,
+// for example "#/$defs/port". All other keywords are ignored. Patterns use
+// the Go regular expression syntax.
+func validateSchema(schemaBytes, document []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(schemaBytes))
+	decoder.UseNumber()
+	var schema any
+	if err := decoder.Decode(&schema); err != nil {
+		return fmt.Errorf("%s: %w", err, ErrInvalidSchema)
+	}
+
+	value, err := decodeTree(document)
+	if err != nil {
+		return newJsonparserError(nil, err)
+	}
+
+	validator := &schemaValidator{root: schema}
+	if err := validator.validate(schema, value, ""); err != nil {
+		return err
+	}
+	if len(validator.violations) > 0 {
+		return &SchemaError{Violations: validator.violations}
+	}
+	return nil
+}
+
+// decodeTree decodes a JSON document into maps, slices, strings, booleans,
+// nil and json.Number, while tolerating the same things the JSON source
+// does, such as trailing commas in objects.
+func decodeTree(data []byte) (any, error) {
+	value, dataType, _, err := jsonparser.Get(data)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTreeValue(value, dataType)
+}
+
+func decodeTreeValue(value []byte, dataType jsonparser.ValueType) (any, error) {
+	switch dataType {
+	case jsonparser.Object:
+		object := make(map[string]any)
+		err := jsonparser.ObjectEach(value, func(key, memberValue []byte, memberType jsonparser.ValueType, _ int) error {
+			decoded, err := decodeTreeValue(memberValue, memberType)
+			object[string(key)] = decoded
+			return err
+		})
+		return object, err
+	case jsonparser.Array:
+		array := []any{}
+		var decodeErr error
+		_, err := jsonparser.ArrayEach(value, func(itemValue []byte, itemType jsonparser.ValueType, _ int, _ error) {
+			decoded, err := decodeTreeValue(itemValue, itemType)
+			if err != nil && decodeErr == nil {
+				decodeErr = err
+			}
+			array = append(array, decoded)
+		})
+		if err == nil {
+			err = decodeErr
+		}
+		return array, err
+	case jsonparser.String:
+		return jsonparser.ParseString(value)
+	case jsonparser.Number:
+		// jsonparser doesn't validate numbers.
+		if _, err := strconv.ParseFloat(string(value), 64); err != nil {
+			return nil, jsonparser.MalformedValueError
+		}
+		return json.Number(value), nil
+	case jsonparser.Boolean:
+		return jsonparser.ParseBoolean(value)
+	case jsonparser.Null:
+		return nil, nil
+	}
+	return nil, jsonparser.UnknownValueTypeError
+}
+
+type schemaValidator struct {
+	root       any
+	violations []SchemaViolation
+	// depth protects against infinite recursion caused by $ref cycles.
+	depth int
+}
+
+func (v *schemaValidator) report(pointer, format string, arguments ...any) {
+	v.violations = append(v.violations, SchemaViolation{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, arguments...),
+	})
+}
+
+// validate checks value against schema and records all violations. An error
+// is only returned if the schema itself is invalid.
+func (v *schemaValidator) validate(schema, value any, pointer string) error {
+	v.depth++
+	defer func() {
+		v.depth--
+	}()
+	if v.depth > 1000 {
+		return fmt.Errorf("schema references are nested too deeply: %w", ErrInvalidSchema)
+	}
+
+	switch typedSchema := schema.(type) {
+	case bool:
+		if !typedSchema {
+			v.report(pointer, "isn't allowed")
+		}
+		return nil
+	case map[string]any:
+		return v.validateKeywords(typedSchema, value, pointer)
+	}
+	return fmt.Errorf("schema at '%s' has to be an object or a boolean: %w", pointer, ErrInvalidSchema)
+}
+
+func (v *schemaValidator) validateKeywords(schema map[string]any, value any, pointer string) error {
+	if reference, ok := schema["$ref"].(string); ok {
+		referenced, err := v.resolve(reference)
+		if err != nil {
+			return err
+		}
+		if err := v.validate(referenced, value, pointer); err != nil {
+			return err
+		}
+	}
+
+	if allOf, ok := schema["allOf"].([]any); ok {
+		for _, subSchema := range allOf {
+			if err := v.validate(subSchema, value, pointer); err != nil {
+				return err
+			}
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		matched, err := v.matchesAnySchema(anyOf, value, pointer)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			v.report(pointer, "doesn't match any of the allowed schemas")
+		}
+	}
+
+	if types, ok := schema["type"]; ok {
+		if !matchesAnyType(types, value) {
+			v.report(pointer, "has type %s, expected %s", typeName(value), formatTypes(types))
+			// Other keywords would only cause follow-up violations.
+			return nil
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		var found bool
+		for _, option := range enum {
+			if jsonEqual(option, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.report(pointer, "isn't one of the allowed values")
+		}
+	}
+	if constant, ok := schema["const"]; ok && !jsonEqual(constant, value) {
+		v.report(pointer, "isn't the allowed value")
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]any:
+		return v.validateObject(schema, typedValue, pointer)
+	case []any:
+		return v.validateArray(schema, typedValue, pointer)
+	case string:
+		return v.validateString(schema, typedValue, pointer)
+	case json.Number:
+		return v.validateNumber(schema, typedValue, pointer)
+	}
+	return nil
+}
+
+// matchesAnySchema checks whether the value matches at least one of the
+// given schemas. The violations of the schemas that don't match aren't
+// reported.
+func (v *schemaValidator) matchesAnySchema(schemas []any, value any, pointer string) (bool, error) {
+	violations := v.violations
+	defer func() {
+		v.violations = violations
+	}()
+
+	for _, subSchema := range schemas {
+		v.violations = nil
+		if err := v.validate(subSchema, value, pointer); err != nil {
+			return false, err
+		}
+		if len(v.violations) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (v *schemaValidator) validateObject(schema map[string]any, object map[string]any, pointer string) error {
+	if required, ok := schema["required"].([]any); ok {
+		for _, key := range required {
+			if keyString, ok := key.(string); ok {
+				if _, present := object[keyString]; !present {
+					v.report(pointer, "is missing required property '%s'", keyString)
+				}
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	patternProperties, _ := schema["patternProperties"].(map[string]any)
+	additionalProperties, hasAdditionalProperties := schema["additionalProperties"]
+
+	// Sorted, so that violations are reported in a deterministic order.
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		memberPointer := pointer + "/" + escapePointerToken(key)
+		var matched bool
+		if propertySchema, ok := properties[key]; ok {
+			matched = true
+			if err := v.validate(propertySchema, object[key], memberPointer); err != nil {
+				return err
+			}
+		}
+		for pattern, patternSchema := range patternProperties {
+			expression, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("pattern '%s': %s: %w", pattern, err, ErrInvalidSchema)
+			}
+			if expression.MatchString(key) {
+				matched = true
+				if err := v.validate(patternSchema, object[key], memberPointer); err != nil {
+					return err
+				}
+			}
+		}
+
+		if !matched && hasAdditionalProperties {
+			if allowed, ok := additionalProperties.(bool); ok && !allowed {
+				v.report(memberPointer, "isn't an allowed property")
+			} else if err := v.validate(additionalProperties, object[key], memberPointer); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) validateArray(schema map[string]any, array []any, pointer string) error {
+	if limit, ok := schemaNumber(schema, "minItems"); ok && float64(len(array)) < limit {
+		v.report(pointer, "has %d items, expected at least %v", len(array), limit)
+	}
+	if limit, ok := schemaNumber(schema, "maxItems"); ok && float64(len(array)) > limit {
+		v.report(pointer, "has %d items, expected at most %v", len(array), limit)
+	}
+
+	if items, ok := schema["items"]; ok {
+		for index, item := range array {
+			if err := v.validate(items, item, pointer+"/"+strconv.Itoa(index)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) validateString(schema map[string]any, value, pointer string) error {
+	length := float64(utf8.RuneCountInString(value))
+	if limit, ok := schemaNumber(schema, "minLength"); ok && length < limit {
+		v.report(pointer, "has length %v, expected at least %v", length, limit)
+	}
+	if limit, ok := schemaNumber(schema, "maxLength"); ok && length > limit {
+		v.report(pointer, "has length %v, expected at most %v", length, limit)
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		expression, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("pattern '%s': %s: %w", pattern, err, ErrInvalidSchema)
+		}
+		if !expression.MatchString(value) {
+			v.report(pointer, "doesn't match pattern '%s'", pattern)
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) validateNumber(schema map[string]any, value json.Number, pointer string) error {
+	number, _ := value.Float64()
+	if limit, ok := schemaNumber(schema, "minimum"); ok && number < limit {
+		v.report(pointer, "is %s, expected at least %v", value, limit)
+	}
+	if limit, ok := schemaNumber(schema, "maximum"); ok && number > limit {
+		v.report(pointer, "is %s, expected at most %v", value, limit)
+	}
+	if limit, ok := schemaNumber(schema, "exclusiveMinimum"); ok && number <= limit {
+		v.report(pointer, "is %s, expected more than %v", value, limit)
+	}
+	if limit, ok := schemaNumber(schema, "exclusiveMaximum"); ok && number >= limit {
+		v.report(pointer, "is %s, expected less than %v", value, limit)
+	}
+	return nil
+}
+
+// resolve looks up a reference such as "#/$defs/port" in the schema.
+func (v *schemaValidator) resolve(reference string) (any, error) {
+	if !strings.HasPrefix(reference, "#") {
+		return nil, fmt.Errorf("reference '%s' doesn't point into the schema: %w", reference, ErrInvalidSchema)
+	}
+
+	current := v.root
+	pointer := strings.TrimPrefix(reference, "#")
+	if pointer == "" {
+		return current, nil
+	}
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		switch typedCurrent := current.(type) {
+		case map[string]any:
+			next, ok := typedCurrent[token]
+			if !ok {
+				return nil, fmt.Errorf("reference '%s' can't be resolved: %w", reference, ErrInvalidSchema)
+			}
+			current = next
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(typedCurrent) {
+				return nil, fmt.Errorf("reference '%s' can't be resolved: %w", reference, ErrInvalidSchema)
+			}
+			current = typedCurrent[index]
+		default:
+			return nil, fmt.Errorf("reference '%s' can't be resolved: %w", reference, ErrInvalidSchema)
+		}
+	}
+	return current, nil
+}
+
+func escapePointerToken(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+func schemaNumber(schema map[string]any, keyword string) (float64, bool) {
+	number, ok := schema[keyword].(json.Number)
+	if !ok {
+		return 0, false
+	}
+	value, err := number.Float64()
+	return value, err == nil
+}
+
+func matchesAnyType(types, value any) bool {
+	switch typedTypes := types.(type) {
+	case string:
+		return matchesType(typedTypes, value)
+	case []any:
+		for _, typeName := range typedTypes {
+			if name, ok := typeName.(string); ok && matchesType(name, value) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchesType(name string, value any) bool {
+	actual := typeName(value)
+	if name == "number" {
+		return actual == "number" || actual == "integer"
+	}
+	return name == actual
+}
+
+// typeName returns the JSON Schema type of a decoded value.
+func typeName(value any) string {
+	switch typedValue := value.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		// 1.0 is an integer as well.
+		if number, err := typedValue.Float64(); err == nil && number == math.Trunc(number) {
+			return "integer"
+		}
+		return "number"
+	}
+	return "null"
+}
+
+func formatTypes(types any) string {
+	if typeList, ok := types.([]any); ok {
+		names := make([]string, 0, len(typeList))
+		for _, name := range typeList {
+			names = append(names, fmt.Sprint(name))
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(types)
+}
+
+// jsonEqual compares two decoded values, treating numbers with the same
+// value as equal, even if their representation differs.
+func jsonEqual(a, b any) bool {
+	numberA, isNumberA := a.(json.Number)
+	numberB, isNumberB := b.(json.Number)
+	if isNumberA && isNumberB {
+		floatA, errA := numberA.Float64()
+		floatB, errB := numberB.Float64()
+		return errA == nil && errB == nil && floatA == floatB
+	}
+
+	switch typedA := a.(type) {
+	case map[string]any:
+		typedB, ok := b.(map[string]any)
+		if !ok || len(typedA) != len(typedB) {
+			return false
+		}
+		for key, valueA := range typedA {
+			valueB, ok := typedB[key]
+			if !ok || !jsonEqual(valueA, valueB) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		typedB, ok := b.([]any)
+		if !ok || len(typedA) != len(typedB) {
+			return false
+		}
+		for index := range typedA {
+			if !jsonEqual(typedA[index], typedB[index]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
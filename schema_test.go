@@ -0,0 +1,177 @@
+package yagcl_json
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["name"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+		"level": {"enum": ["debug", "info"]},
+		"ratio": {"type": "number", "exclusiveMinimum": 0, "maximum": 1},
+		"hosts": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+		"database": {
+			"type": "object",
+			"properties": {
+				"port": {"$ref": "#/$defs/port"}
+			}
+		}
+	},
+	"$defs": {
+		"port": {"type": "integer", "minimum": 1, "maximum": 65535}
+	}
+}`
+
+type schemaConfiguration struct {
+	Name     string   `key:"name"`
+	Level    string   `key:"level"`
+	Ratio    float64  `key:"ratio"`
+	Hosts    []string `key:"hosts"`
+	Database struct {
+		Port int `key:"port"`
+	} `key:"database"`
+}
+
+func Test_Parse_Schema_Valid(t *testing.T) {
+	var c schemaConfiguration
+	err := yagcl.New[schemaConfiguration]().
+		Add(Source().String(`{
+			// Comments and trailing commas are tolerated.
+			"name": "abc",
+			"level": "info",
+			"ratio": 0.5,
+			"hosts": ["a", "b"],
+			"database": {"port": 5432},
+		}`).Schema([]byte(testSchema))).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "abc", c.Name)
+		assert.Equal(t, 5432, c.Database.Port)
+	}
+}
+
+func Test_Parse_Schema_Violations(t *testing.T) {
+	for _, value := range []struct {
+		document   string
+		violations []SchemaViolation
+	}{
+		{`{}`, []SchemaViolation{{"", "is missing required property 'name'"}}},
+		{`[]`, []SchemaViolation{{"", "has type array, expected object"}}},
+		{`{"name": 1}`, []SchemaViolation{{"/name", "has type integer, expected string"}}},
+		{`{"name": "ABC"}`, []SchemaViolation{{"/name", "doesn't match pattern '^[a-z]+$'"}}},
+		{`{"name": "a", "level": "trace"}`, []SchemaViolation{{"/level", "isn't one of the allowed values"}}},
+		{`{"name": "a", "ratio": 0}`, []SchemaViolation{{"/ratio", "is 0, expected more than 0"}}},
+		{`{"name": "a", "hosts": ["a", 1, "c"]}`, []SchemaViolation{
+			{"/hosts", "has 3 items, expected at most 2"},
+			{"/hosts/1", "has type integer, expected string"},
+		}},
+		{`{"name": "a", "database": {"port": 0.5}}`, []SchemaViolation{{"/database/port", "has type number, expected integer"}}},
+		{`{"name": "a", "database": {"port": 70000}}`, []SchemaViolation{{"/database/port", "is 70000, expected at most 65535"}}},
+		{`{"name": "", "a/b": 1, "c~d": 2}`, []SchemaViolation{
+			{"/a~1b", "isn't an allowed property"},
+			{"/c~0d", "isn't an allowed property"},
+			{"/name", "has length 0, expected at least 1"},
+			{"/name", "doesn't match pattern '^[a-z]+$'"},
+		}},
+	} {
+		t.Run(value.document, func(t *testing.T) {
+			var c schemaConfiguration
+			err := yagcl.New[schemaConfiguration]().
+				Add(Source().String(value.document).Schema([]byte(testSchema))).
+				Parse(&c)
+
+			assert.ErrorIs(t, err, ErrSchemaViolation)
+			var schemaErr *SchemaError
+			if assert.True(t, errors.As(err, &schemaErr)) {
+				assert.Equal(t, value.violations, schemaErr.Violations)
+			}
+			// Nothing is parsed if the document is invalid.
+			assert.Empty(t, c.Name)
+		})
+	}
+}
+
+func Test_Parse_Schema_BooleanAndAllOf(t *testing.T) {
+	type configuration struct {
+		Name string `key:"name"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": "a", "other": {}}`).Schema([]byte(`{
+			"allOf": [{"required": ["name"]}],
+			"properties": {"other": false}
+		}`))).
+		Parse(&c)
+	var schemaErr *SchemaError
+	if assert.True(t, errors.As(err, &schemaErr)) {
+		assert.Equal(t, []SchemaViolation{{"/other", "isn't allowed"}}, schemaErr.Violations)
+	}
+}
+
+func Test_Parse_Schema_AnyOf(t *testing.T) {
+	type configuration struct {
+		Port any `key:"port"`
+	}
+
+	schema := []byte(`{
+		"properties": {
+			"port": {"anyOf": [{"type": "integer", "minimum": 1}, {"type": "null"}]}
+		}
+	}`)
+	for _, document := range []string{`{"port": 1}`, `{"port": null}`} {
+		var c configuration
+		err := yagcl.New[configuration]().
+			Add(Source().String(document).Schema(schema)).
+			Parse(&c)
+		assert.NoError(t, err, document)
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"port": 0}`).Schema(schema)).
+		Parse(&c)
+	var schemaErr *SchemaError
+	if assert.True(t, errors.As(err, &schemaErr)) {
+		assert.Equal(t, []SchemaViolation{{"/port", "doesn't match any of the allowed schemas"}}, schemaErr.Violations)
+	}
+}
+
+func Test_Parse_Schema_Invalid(t *testing.T) {
+	type configuration struct {
+		Name string `key:"name"`
+	}
+
+	for _, schema := range []string{
+		`{`,
+		`1`,
+		`{"$ref": "https://example.com/schema.json"}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"$ref": "#"}`,
+		`{"properties": {"name": {"pattern": "("}}}`,
+	} {
+		t.Run(schema, func(t *testing.T) {
+			var c configuration
+			err := yagcl.New[configuration]().
+				Add(Source().String(`{"name": "a"}`).Schema([]byte(schema))).
+				Parse(&c)
+			assert.ErrorIs(t, err, ErrInvalidSchema)
+		})
+	}
+}
+
+func Test_SchemaError_Error(t *testing.T) {
+	err := &SchemaError{Violations: []SchemaViolation{
+		{"/a", "isn't allowed"},
+		{"", "is missing required property 'b'"},
+	}}
+	assert.Equal(t, "'/a' isn't allowed; '' is missing required property 'b': document violates schema", err.Error())
+}
@@ -1395,3 +1395,100 @@ func Test_Parse_EmptyStructPointer(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func Test_Parse_Struct_IncorrectType(t *testing.T) {
+	type configuration struct {
+		FieldA struct {
+			FieldB string `key:"field_b"`
+		} `key:"field_a"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"field_a": "not an object"}`)).
+		Parse(&c)
+	assert.ErrorIs(t, err, yagcl.ErrParseValue)
+}
+
+func Test_Parse_Null(t *testing.T) {
+	type nested struct {
+		Field string `key:"field"`
+	}
+	type configuration struct {
+		String  string            `key:"string"`
+		Int     int               `key:"int"`
+		Pointer *int              `key:"pointer"`
+		Struct  nested            `key:"struct"`
+		Nested  *nested           `key:"nested"`
+		Slice   []string          `key:"slice"`
+		Map     map[string]string `key:"map"`
+		Custom  customJSONUnmarshalable
+	}
+	document := `{
+		"string": null,
+		"int": null,
+		"pointer": null,
+		"struct": null,
+		"nested": null,
+		"slice": null,
+		"map": null,
+		"custom": null
+	}`
+	newConfiguration := func() configuration {
+		number := 1
+		return configuration{
+			String:  "default",
+			Int:     1,
+			Pointer: &number,
+			Struct:  nested{Field: "default"},
+			Nested:  &nested{Field: "default"},
+			Slice:   []string{"default"},
+			Map:     map[string]string{"default": "default"},
+			Custom:  "DEFAULT",
+		}
+	}
+
+	t.Run("keep", func(t *testing.T) {
+		c := newConfiguration()
+		err := yagcl.New[configuration]().
+			Add(Source().String(document).KeyNaming(SnakeCase)).
+			Parse(&c)
+		if assert.NoError(t, err) {
+			assert.Equal(t, newConfiguration(), c)
+		}
+	})
+	t.Run("reset", func(t *testing.T) {
+		c := newConfiguration()
+		err := yagcl.New[configuration]().
+			Add(Source().String(document).KeyNaming(SnakeCase).Null(NullReset)).
+			Parse(&c)
+		if assert.NoError(t, err) {
+			assert.Equal(t, configuration{}, c)
+		}
+	})
+	t.Run("error", func(t *testing.T) {
+		for _, key := range []string{"string", "int", "pointer", "struct", "nested", "slice", "map", "custom"} {
+			t.Run(key, func(t *testing.T) {
+				c := newConfiguration()
+				err := yagcl.New[configuration]().
+					Add(Source().String(`{"` + key + `": null}`).KeyNaming(SnakeCase).Null(NullError)).
+					Parse(&c)
+				assert.ErrorIs(t, err, ErrNullValue)
+			})
+		}
+	})
+}
+
+func Test_Parse_Null_EmbeddedPointer(t *testing.T) {
+	type configuration struct {
+		*CommonConfig
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{"name": null}`).Null(NullReset)).
+		Parse(&c)
+	if assert.NoError(t, err) {
+		assert.Nil(t, c.CommonConfig)
+	}
+}
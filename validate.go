@@ -0,0 +1,251 @@
+package yagcl_json
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrValidationFailed is wrapped by ValidationError and returned if a value
+// violates a rule defined via the `validate` tag. Rules are evaluated for
+// values found in the document and for defaults. Keys missing from the
+// document aren't validated at all, not even by the nonempty rule, as the
+// field might have been set by another source. Combine the rules with
+// `required:"true"` to make sure the key is present.
+var ErrValidationFailed = errors.New("value failed validation")
+
+// ErrInvalidValidationRule is returned if the `validate` tag of a field
+// contains an unknown or malformed rule.
+var ErrInvalidValidationRule = errors.New("invalid validation rule")
+
+// ValidationError describes a value that violates a rule defined via the
+// `validate` tag.
+type ValidationError struct {
+	// Path is the JSON path of the value, for example "server.port".
+	Path string
+	// Line and Column describe the position of the value in the document,
+	// both starting at 1. They are 0 for values that don't originate from
+	// the document, such as defaults.
+	Line   int
+	Column int
+	// Rule is the violated rule, for example "max=65535".
+	Rule string
+	// Message describes the violation.
+	Message string
+}
+
+// Error implements error.Error.
+func (e *ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("field '%s' (line %d, column %d) violates rule '%s': %s: %s", e.Path, e.Line, e.Column, e.Rule, e.Message, ErrValidationFailed)
+	}
+	return fmt.Sprintf("field '%s' violates rule '%s': %s: %s", e.Path, e.Rule, e.Message, ErrValidationFailed)
+}
+
+// Unwrap allows checking for ErrValidationFailed using errors.Is.
+func (e *ValidationError) Unwrap() error {
+	return ErrValidationFailed
+}
+
+// validate checks the parsed value of a field against the rules defined in its
+// `validate` tag. Rules are separated by commas. The following rules are
+// supported:
+//
+//   - min=N and max=N limit numbers. For strings, slices, arrays and maps
+//     they limit the length instead. Durations may use units, such as "5s".
+//   - len=N requires strings, slices, arrays and maps to have exactly the
+//     given length.
+//   - oneof=a b c requires the value to be one of the space separated values.
+//   - nonempty requires the value not to be empty or zero.
+//   - url requires a string to be an absolute URL, such as
+//     "https://example.com".
+//   - hostport requires a string to be a host and a port, such as
+//     "localhost:8080". The host may be omitted.
+//   - regex=EXPRESSION requires a string to match the regular expression.
+//     Since expressions may contain commas, this has to be the last rule.
+//
+// Nil pointers and keys missing from the document are never validated, use
+// the `required` tag instead.
+func (s *jsonSourceImpl) validate(structField reflect.StructField, jsonPath []string, valueOffset int, parsed reflect.Value) error {
+	tag := structField.Tag.Get("validate")
+	if tag == "" {
+		return nil
+	}
+
+	value := parsed
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	for _, rule := range splitValidationRules(tag) {
+		name, argument, _ := strings.Cut(rule, "=")
+		message, err := checkValidationRule(name, argument, value)
+		if err != nil {
+			return fmt.Errorf("rule '%s' of field '%s' (%s): %w", rule, structField.Name, err, ErrInvalidValidationRule)
+		}
+		if message == "" {
+			continue
+		}
+
+		validationErr := &ValidationError{
+			Path:    formatPath(jsonPath),
+			Rule:    rule,
+			Message: message,
+		}
+		if valueOffset >= 0 {
+			validationErr.Line, validationErr.Column = position(s.document, valueOffset)
+		}
+		return validationErr
+	}
+	return nil
+}
+
+func splitValidationRules(tag string) []string {
+	var rules []string
+	for tag != "" {
+		if strings.HasPrefix(tag, "regex=") {
+			return append(rules, tag)
+		}
+		rule, remainder, _ := strings.Cut(tag, ",")
+		rules = append(rules, strings.TrimSpace(rule))
+		tag = strings.TrimSpace(remainder)
+	}
+	return rules
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// checkValidationRule returns a message describing the violation of the
+// given rule or an empty string if the value is valid. An error is returned
+// if the rule can't be applied to the value.
+func checkValidationRule(name, argument string, value reflect.Value) (string, error) {
+	switch name {
+	case "min", "max", "len":
+		if hasLength(value) {
+			limit, err := strconv.Atoi(argument)
+			if err != nil {
+				return "", err
+			}
+			length := value.Len()
+			if value.Kind() == reflect.String {
+				length = utf8.RuneCountInString(value.String())
+			}
+			if name == "min" && length < limit {
+				return fmt.Sprintf("length %d is less than %d", length, limit), nil
+			}
+			if name == "max" && length > limit {
+				return fmt.Sprintf("length %d is greater than %d", length, limit), nil
+			}
+			if name == "len" && length != limit {
+				return fmt.Sprintf("length %d isn't %d", length, limit), nil
+			}
+			return "", nil
+		}
+		if name == "len" {
+			return "", fmt.Errorf("can't be applied to %s", value.Type())
+		}
+
+		number, limit, err := numbers(value, argument)
+		if err != nil {
+			return "", err
+		}
+		if name == "min" && number < limit {
+			return fmt.Sprintf("value %v is less than %s", value.Interface(), argument), nil
+		}
+		if name == "max" && number > limit {
+			return fmt.Sprintf("value %v is greater than %s", value.Interface(), argument), nil
+		}
+		return "", nil
+	case "oneof":
+		formatted := fmt.Sprint(value.Interface())
+		for _, option := range strings.Fields(argument) {
+			if option == formatted {
+				return "", nil
+			}
+		}
+		return fmt.Sprintf("value %s isn't one of [%s]", formatted, argument), nil
+	case "nonempty":
+		if hasLength(value) && value.Len() == 0 || value.IsZero() {
+			return "value is empty", nil
+		}
+		return "", nil
+	case "regex":
+		if value.Kind() != reflect.String {
+			return "", fmt.Errorf("can't be applied to %s", value.Type())
+		}
+		expression, err := regexp.Compile(argument)
+		if err != nil {
+			return "", err
+		}
+		if !expression.MatchString(value.String()) {
+			return fmt.Sprintf("value '%s' doesn't match", value.String()), nil
+		}
+		return "", nil
+	case "url":
+		if value.Kind() != reflect.String {
+			return "", fmt.Errorf("can't be applied to %s", value.Type())
+		}
+		parsed, err := url.Parse(value.String())
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return fmt.Sprintf("value '%s' isn't an absolute URL", value.String()), nil
+		}
+		return "", nil
+	case "hostport":
+		if value.Kind() != reflect.String {
+			return "", fmt.Errorf("can't be applied to %s", value.Type())
+		}
+		_, port, err := net.SplitHostPort(value.String())
+		if err == nil {
+			_, err = strconv.ParseUint(port, 10, 16)
+		}
+		if err != nil {
+			return fmt.Sprintf("value '%s' isn't of the form host:port", value.String()), nil
+		}
+		return "", nil
+	}
+
+	return "", errors.New("unknown rule")
+}
+
+func hasLength(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// numbers converts the value and the limit to float64, so that they can be
+// compared.
+func numbers(value reflect.Value, limit string) (float64, float64, error) {
+	if value.Type() == durationType {
+		if duration, err := time.ParseDuration(limit); err == nil {
+			return float64(value.Int()), float64(duration), nil
+		}
+	}
+
+	parsedLimit, err := strconv.ParseFloat(limit, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), parsedLimit, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(value.Uint()), parsedLimit, nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), parsedLimit, nil
+	}
+	return 0, 0, fmt.Errorf("can't be applied to %s", value.Type())
+}
@@ -0,0 +1,158 @@
+package yagcl_json
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse_Validate_Valid(t *testing.T) {
+	type configuration struct {
+		Port     int               `key:"port" validate:"min=1,max=65535"`
+		Ratio    *float64          `key:"ratio" validate:"min=0, max=1"`
+		Level    string            `key:"level" validate:"oneof=debug info warn"`
+		Workers  uint              `key:"workers" validate:"oneof=1 2 4"`
+		Name     string            `key:"name" validate:"nonempty,max=8,regex=^[a-z]{1,3}(,[a-z]+)?$"`
+		Hosts    []string          `key:"hosts" validate:"nonempty,max=2"`
+		Labels   map[string]string `key:"labels" validate:"len=1"`
+		Endpoint string            `key:"endpoint" validate:"url"`
+		Listen   string            `key:"listen" validate:"hostport"`
+		Timeout  time.Duration     `key:"timeout" validate:"min=1s,max=1m"`
+		Missing  string            `key:"missing" validate:"nonempty"`
+		Nil      *int              `key:"nil" validate:"min=1"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{
+			"port": 8080,
+			"ratio": 0.5,
+			"level": "info",
+			"workers": 4,
+			"name": "ab,cd",
+			"hosts": ["a", "b"],
+			"labels": {"a": "b"},
+			"endpoint": "https://example.com/path",
+			"listen": ":8080",
+			"timeout": "30s",
+			"nil": null
+		}`).Null(NullReset)).
+		Parse(&c)
+	assert.NoError(t, err)
+}
+
+func Test_Parse_Validate_Required(t *testing.T) {
+	type configuration struct {
+		Name string `key:"name" validate:"nonempty" required:"true"`
+	}
+
+	var c configuration
+	assert.ErrorIs(t, parseString(&c, `{}`), yagcl.ErrValueNotSet)
+	assert.ErrorIs(t, parseString(&c, `{"name": ""}`), ErrValidationFailed)
+	assert.NoError(t, parseString(&c, `{"name": "a"}`))
+}
+
+func Test_Parse_Validate_Invalid(t *testing.T) {
+	type configuration struct {
+		Port     int               `key:"port" validate:"min=1,max=65535"`
+		Level    string            `key:"level" validate:"oneof=debug info warn"`
+		Name     string            `key:"name" validate:"nonempty,regex=^[a-z]+$"`
+		Short    string            `key:"short" validate:"max=2"`
+		Hosts    []string          `key:"hosts" validate:"nonempty"`
+		Labels   map[string]string `key:"labels" validate:"min=1"`
+		Endpoint string            `key:"endpoint" validate:"url"`
+		Listen   string            `key:"listen" validate:"hostport"`
+		Timeout  time.Duration     `key:"timeout" validate:"max=1m"`
+		Nested   struct {
+			Port *int `key:"port" validate:"max=10"`
+		} `key:"nested"`
+	}
+
+	for _, value := range []struct {
+		document string
+		path     string
+		rule     string
+		line     int
+		column   int
+	}{
+		{`{"port": 0}`, "port", "min=1", 1, 10},
+		{"{\n  \"port\": 65536\n}", "port", "max=65535", 2, 11},
+		{`{"level": "trace"}`, "level", "oneof=debug info warn", 1, 11},
+		{`{"name": ""}`, "name", "nonempty", 1, 10},
+		{`{"name": "A"}`, "name", "regex=^[a-z]+$", 1, 10},
+		{`{"short": "äöü"}`, "short", "max=2", 1, 11},
+		{`{"hosts": []}`, "hosts", "nonempty", 1, 11},
+		{`{"labels": {}}`, "labels", "min=1", 1, 12},
+		{`{"endpoint": "example.com"}`, "endpoint", "url", 1, 14},
+		{`{"listen": "localhost"}`, "listen", "hostport", 1, 12},
+		{`{"listen": "localhost:99999"}`, "listen", "hostport", 1, 12},
+		{`{"timeout": "2m"}`, "timeout", "max=1m", 1, 13},
+		{`{"nested": {"port": 11}}`, "nested.port", "max=10", 1, 21},
+	} {
+		t.Run(value.document, func(t *testing.T) {
+			var c configuration
+			err := yagcl.New[configuration]().
+				Add(Source().String(value.document)).
+				Parse(&c)
+			assert.ErrorIs(t, err, ErrValidationFailed)
+			var validationErr *ValidationError
+			if assert.True(t, errors.As(err, &validationErr)) {
+				assert.Equal(t, value.path, validationErr.Path)
+				assert.Equal(t, value.rule, validationErr.Rule)
+				assert.Equal(t, value.line, validationErr.Line)
+				assert.Equal(t, value.column, validationErr.Column)
+			}
+		})
+	}
+}
+
+func Test_Parse_Validate_Default(t *testing.T) {
+	type configuration struct {
+		Port int `key:"port" default:"0" validate:"min=1"`
+	}
+
+	var c configuration
+	err := yagcl.New[configuration]().
+		Add(Source().String(`{}`)).
+		Parse(&c)
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) {
+		assert.Equal(t, "field 'port' violates rule 'min=1': value 0 is less than 1: "+ErrValidationFailed.Error(), validationErr.Error())
+	}
+}
+
+func Test_Parse_Validate_InvalidRule(t *testing.T) {
+	t.Run("unknown", func(t *testing.T) {
+		var c struct {
+			Port int `key:"port" validate:"positive"`
+		}
+		assert.ErrorIs(t, parseString(&c, `{"port": 1}`), ErrInvalidValidationRule)
+	})
+	t.Run("not a number", func(t *testing.T) {
+		var c struct {
+			Port int `key:"port" validate:"min=one"`
+		}
+		assert.ErrorIs(t, parseString(&c, `{"port": 1}`), ErrInvalidValidationRule)
+	})
+	t.Run("wrong type", func(t *testing.T) {
+		var c struct {
+			Port int `key:"port" validate:"url"`
+		}
+		assert.ErrorIs(t, parseString(&c, `{"port": 1}`), ErrInvalidValidationRule)
+	})
+	t.Run("invalid regex", func(t *testing.T) {
+		var c struct {
+			Name string `key:"name" validate:"regex=("`
+		}
+		assert.ErrorIs(t, parseString(&c, `{"name": "a"}`), ErrInvalidValidationRule)
+	})
+}
+
+// parseString parses the given document into the configuration, using a
+// source without any options.
+func parseString[T any](configuration *T, document string) error {
+	return yagcl.New[T]().Add(Source().String(document)).Parse(configuration)
+}
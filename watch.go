@@ -0,0 +1,158 @@
+package yagcl_json
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+)
+
+const (
+	defaultWatchInterval = time.Second
+	defaultWatchDebounce = 200 * time.Millisecond
+)
+
+// Watcher reloads a configuration file whenever it changes. Changes are
+// detected by polling the file's metadata, which also detects editors
+// replacing the file via rename, as the path then refers to a different
+// file.
+type Watcher[T any] struct {
+	path      string
+	interval  time.Duration
+	debounce  time.Duration
+	source    func(JSONSourceOptionalSetup[*jsonSourceImpl]) JSONSourceOptionalSetup[*jsonSourceImpl]
+	newLoader func(yagcl.Source) yagcl.YAGCL[T]
+}
+
+// NewWatcher creates a Watcher for the given file path, polling every second
+// with a debounce of 200 milliseconds. By default, the file is loaded via a
+// mandatory JSON source without any options, which can be changed via
+// Source and Loader.
+func NewWatcher[T any](path string) *Watcher[T] {
+	return &Watcher[T]{
+		path:     path,
+		interval: defaultWatchInterval,
+		debounce: defaultWatchDebounce,
+	}
+}
+
+// Interval defines how often the file is checked for changes.
+func (w *Watcher[T]) Interval(interval time.Duration) *Watcher[T] {
+	w.interval = interval
+	return w
+}
+
+// Debounce defines how long the file has to stay unchanged before it is
+// reloaded. This prevents reading files that are still being written.
+func (w *Watcher[T]) Debounce(debounce time.Duration) *Watcher[T] {
+	w.debounce = debounce
+	return w
+}
+
+// Source configures the JSON source reading the watched file. configure
+// receives the source created via Source().Path(path).Must() and returns the
+// source to use, for example with a KeyMatchingPolicy.
+func (w *Watcher[T]) Source(configure func(JSONSourceOptionalSetup[*jsonSourceImpl]) JSONSourceOptionalSetup[*jsonSourceImpl]) *Watcher[T] {
+	w.source = configure
+	return w
+}
+
+// Loader defines how the yagcl instance loading the configuration is
+// created. newLoader receives the JSON source reading the watched file, so
+// that additional sources and options, such as AllowOverride, can be added.
+// Only changes of the watched file trigger a reload.
+func (w *Watcher[T]) Loader(newLoader func(source yagcl.Source) yagcl.YAGCL[T]) *Watcher[T] {
+	w.newLoader = newLoader
+	return w
+}
+
+// Watch loads the configuration and returns it. Afterwards, the file is
+// watched in the background until the context is done. Each change is
+// parsed into a fresh T. If parsing, including validation, succeeds and the
+// result differs from the active configuration, onChange is called with the
+// old and the new configuration and the new configuration becomes active.
+// Otherwise onChange is called with the active configuration, the zero value
+// and the error, while the active configuration stays as it is. onChange is
+// always called from the same goroutine.
+func (w *Watcher[T]) Watch(ctx context.Context, onChange func(old, new T, err error)) (T, error) {
+	// Checked before loading, so that no change is missed.
+	lastInfo, _ := os.Stat(w.path)
+	current, err := w.load()
+	if err != nil {
+		return current, err
+	}
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		var pending bool
+		var lastChange time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				info, err := os.Stat(w.path)
+				if err != nil {
+					// The file might be in the middle of being replaced.
+					continue
+				}
+				if fileChanged(lastInfo, info) {
+					lastInfo = info
+					lastChange = now
+					pending = true
+					continue
+				}
+				if !pending || now.Sub(lastChange) < w.debounce {
+					continue
+				}
+
+				pending = false
+				fresh, err := w.load()
+				if err != nil {
+					var zero T
+					onChange(current, zero, err)
+				} else if !reflect.DeepEqual(current, fresh) {
+					old := current
+					current = fresh
+					onChange(old, fresh, nil)
+				}
+			}
+		}
+	}()
+
+	return current, nil
+}
+
+func (w *Watcher[T]) load() (T, error) {
+	var source JSONSourceOptionalSetup[*jsonSourceImpl] = Source().Path(w.path).Must()
+	if w.source != nil {
+		source = w.source(source)
+	}
+	loader := yagcl.New[T]().Add(source)
+	if w.newLoader != nil {
+		loader = w.newLoader(source)
+	}
+
+	var configuration T
+	err := loader.Parse(&configuration)
+	return configuration, err
+}
+
+// fileChanged checks whether the file has been modified or replaced.
+func fileChanged(old, new os.FileInfo) bool {
+	if old == nil {
+		return true
+	}
+	return !os.SameFile(old, new) ||
+		!old.ModTime().Equal(new.ModTime()) ||
+		old.Size() != new.Size()
+}
+
+// Watch is a shorthand for NewWatcher(path).Watch(ctx, onChange).
+func Watch[T any](ctx context.Context, path string, onChange func(old, new T, err error)) (T, error) {
+	return NewWatcher[T](path).Watch(ctx, onChange)
+}
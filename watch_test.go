@@ -0,0 +1,137 @@
+package yagcl_json
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bios-Marcel/yagcl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchConfiguration struct {
+	Port int `key:"port" validate:"min=1"`
+}
+
+type watchEvent struct {
+	old, new watchConfiguration
+	err      error
+}
+
+func startWatching(t *testing.T, path string) (watchConfiguration, <-chan watchEvent) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	events := make(chan watchEvent, 10)
+	initial, err := NewWatcher[watchConfiguration](path).
+		Interval(5*time.Millisecond).
+		Debounce(20*time.Millisecond).
+		Watch(ctx, func(old, new watchConfiguration, err error) {
+			events <- watchEvent{old: old, new: new, err: err}
+		})
+	require.NoError(t, err)
+	return initial, events
+}
+
+func awaitEvent(t *testing.T, events <-chan watchEvent) watchEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change has been reported")
+		return watchEvent{}
+	}
+}
+
+func Test_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"port": 1}`), 0o600))
+
+	initial, events := startWatching(t, path)
+	assert.Equal(t, 1, initial.Port)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"port": 22}`), 0o600))
+	event := awaitEvent(t, events)
+	if assert.NoError(t, event.err) {
+		assert.Equal(t, 1, event.old.Port)
+		assert.Equal(t, 22, event.new.Port)
+	}
+
+	// Invalid values aren't applied.
+	require.NoError(t, os.WriteFile(path, []byte(`{"port": 0}`), 0o600))
+	event = awaitEvent(t, events)
+	assert.ErrorIs(t, event.err, ErrValidationFailed)
+	assert.Equal(t, 22, event.old.Port)
+
+	// Editors often write a new file and replace the old one.
+	replacement := filepath.Join(filepath.Dir(path), "config.json.tmp")
+	require.NoError(t, os.WriteFile(replacement, []byte(`{"port": 333}`), 0o600))
+	require.NoError(t, os.Rename(replacement, path))
+	event = awaitEvent(t, events)
+	if assert.NoError(t, event.err) {
+		assert.Equal(t, 22, event.old.Port)
+		assert.Equal(t, 333, event.new.Port)
+	}
+}
+
+func Test_Watch_Unchanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"port": 1}`), 0o600))
+
+	_, events := startWatching(t, path)
+	// Same configuration, different document.
+	require.NoError(t, os.WriteFile(path, []byte(`{ "port": 1 }`), 0o600))
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected change: %v", event)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func Test_Watch_InitialError(t *testing.T) {
+	_, err := Watch(context.Background(), filepath.Join(t.TempDir(), "missing.json"),
+		func(old, new watchConfiguration, err error) {})
+	assert.ErrorIs(t, err, yagcl.ErrSourceNotFound)
+}
+
+func Test_Watch_Loader(t *testing.T) {
+	type configuration struct {
+		Port int    `key:"port"`
+		Host string `key:"host"`
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"PORT": 1}`), 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan configuration, 10)
+	initial, err := NewWatcher[configuration](path).
+		Interval(5*time.Millisecond).
+		Debounce(20*time.Millisecond).
+		Source(func(source JSONSourceOptionalSetup[*jsonSourceImpl]) JSONSourceOptionalSetup[*jsonSourceImpl] {
+			return source.KeyMatching(KeyMatchCaseInsensitive)
+		}).
+		Loader(func(source yagcl.Source) yagcl.YAGCL[configuration] {
+			return yagcl.New[configuration]().
+				Add(Source().String(`{"host": "localhost", "port": 2}`)).
+				Add(source).
+				AllowOverride()
+		}).
+		Watch(ctx, func(old, new configuration, err error) {
+			changes <- new
+		})
+	require.NoError(t, err)
+	assert.Equal(t, configuration{Port: 1, Host: "localhost"}, initial)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"Port": 3}`), 0o600))
+	select {
+	case change := <-changes:
+		assert.Equal(t, configuration{Port: 3, Host: "localhost"}, change)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no change has been reported")
+	}
+}